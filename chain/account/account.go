@@ -13,6 +13,7 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/store/protocol"
 	"github.com/LemoFoundationLtd/lemochain-core/store/trie"
 	"math/big"
+	"sort"
 )
 
 var (
@@ -57,6 +58,15 @@ func NewStorageCache(db protocol.ChainDB) *StorageCache {
 	}
 }
 
+// copy returns a cache with the same cached values. The trie is reloaded when it is used
+func (cache *StorageCache) copy() *StorageCache {
+	return &StorageCache{
+		db:     cache.db,
+		cached: cache.cached.Copy(),
+		dirty:  cache.dirty.Copy(),
+	}
+}
+
 func (cache *StorageCache) Reset() {
 	cache.trie = nil
 	cache.cached = make(Storage)
@@ -140,6 +150,37 @@ func (cache *StorageCache) Update(root common.Hash) (common.Hash, error) {
 	return tr.Hash(), nil
 }
 
+// Values returns all not empty values in trie and cache. The keys are not returned because they are hashed in trie
+func (cache *StorageCache) Values(root common.Hash) ([][]byte, error) {
+	values := make(map[common.Hash][]byte)
+	if root != (common.Hash{}) {
+		tr, err := cache.GetTrie(root)
+		if err != nil {
+			log.Errorf("load trie by root 0x%x fail: %v", root, err)
+			return nil, ErrTrieFail
+		}
+		it := trie.NewIterator(tr.NodeIterator(nil))
+		for it.Next() {
+			values[common.BytesToHash(it.Key)] = it.Value
+		}
+		if it.Err != nil {
+			return nil, it.Err
+		}
+	}
+	// the cached values are newer
+	for key, value := range cache.cached {
+		values[crypto.Keccak256Hash(key[:])] = value
+	}
+
+	result := make([][]byte, 0, len(values))
+	for _, value := range values {
+		if len(value) != 0 {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
 func (cache *StorageCache) SetState(key common.Hash, value []byte) error {
 	cache.cached[key] = value
 	cache.dirty[key] = value
@@ -274,6 +315,27 @@ func NewAccount(db protocol.ChainDB, address common.Address, data *types.Account
 	return account
 }
 
+// copy returns a deep copy of account without events
+func (a *Account) copy() *Account {
+	cpy := &Account{
+		data:          a.data.Copy(),
+		db:            a.db,
+		storage:       a.storage.copy(),
+		assetCode:     a.assetCode.copy(),
+		assetId:       a.assetId.copy(),
+		equity:        a.equity.copy(),
+		code:          a.code,
+		codeIsDirty:   a.codeIsDirty,
+		events:        make([]*types.Event, 0),
+		newestRecords: make(map[types.ChangeLogType]uint32, len(a.newestRecords)),
+		suicided:      a.suicided,
+	}
+	for k, v := range a.newestRecords {
+		cpy.newestRecords[k] = v
+	}
+	return cpy
+}
+
 // MarshalJSON encodes the client RPC account format.
 func (a *Account) MarshalJSON() ([]byte, error) {
 	return a.data.MarshalJSON()
@@ -495,6 +557,26 @@ func (a *Account) SetAssetCode(code common.Hash, asset *types.Asset) error {
 	}
 }
 
+// GetAssetCodes returns all assets issued by the account. They are sorted by asset code
+func (a *Account) GetAssetCodes() ([]*types.Asset, error) {
+	values, err := a.assetCode.Values(a.data.AssetCodeRoot)
+	if err != nil {
+		return nil, err
+	}
+	assets := make([]*types.Asset, 0, len(values))
+	for _, val := range values {
+		asset := &types.Asset{TotalSupply: new(big.Int), Profile: make(types.Profile)}
+		if err := rlp.DecodeBytes(val, asset); err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return bytes.Compare(assets[i].AssetCode[:], assets[j].AssetCode[:]) < 0
+	})
+	return assets, nil
+}
+
 func (a *Account) GetAssetCodeTotalSupply(code common.Hash) (*big.Int, error) {
 	asset, err := a.GetAssetCode(code)
 	if err != nil {
@@ -570,6 +652,26 @@ func (a *Account) GetEquityState(id common.Hash) (*types.AssetEquity, error) {
 	}
 }
 
+// GetEquities returns all asset equities owned by the account. They are sorted by asset id
+func (a *Account) GetEquities() ([]*types.AssetEquity, error) {
+	values, err := a.equity.Values(a.data.EquityRoot)
+	if err != nil {
+		return nil, err
+	}
+	equities := make([]*types.AssetEquity, 0, len(values))
+	for _, val := range values {
+		var equity types.AssetEquity
+		if err := rlp.DecodeBytes(val, &equity); err != nil {
+			return nil, err
+		}
+		equities = append(equities, &equity)
+	}
+	sort.Slice(equities, func(i, j int) bool {
+		return bytes.Compare(equities[i].AssetId[:], equities[j].AssetId[:]) < 0
+	})
+	return equities, nil
+}
+
 func (a *Account) SetEquityState(id common.Hash, equity *types.AssetEquity) error {
 	if equity == nil {
 		return a.equity.SetState(id, nil)
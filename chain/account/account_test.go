@@ -261,6 +261,61 @@ func TestAccount_SetStorageState_GetStorageState(t *testing.T) {
 	assert.Empty(t, readValue) // []byte(nil)
 }
 
+func TestAccount_GetAssetCodes_GetEquities(t *testing.T) {
+	ClearData()
+	db := newDB()
+	defer db.Close()
+
+	account := loadAccount(db, defaultAccounts[0].Address)
+	assets, err := account.GetAssetCodes()
+	assert.NoError(t, err)
+	assert.Empty(t, assets)
+
+	// in cache
+	asset1 := &types.Asset{AssetCode: h(2), Issuer: account.GetAddress(), TotalSupply: big.NewInt(100), Profile: make(types.Profile)}
+	asset2 := &types.Asset{AssetCode: h(1), Issuer: account.GetAddress(), TotalSupply: big.NewInt(200), Profile: types.Profile{"name": "lemo"}}
+	assert.NoError(t, account.SetAssetCode(asset1.AssetCode, asset1))
+	assert.NoError(t, account.SetAssetCode(asset2.AssetCode, asset2))
+	equity1 := &types.AssetEquity{AssetCode: h(1), AssetId: h(20), Equity: big.NewInt(10)}
+	equity2 := &types.AssetEquity{AssetCode: h(1), AssetId: h(10), Equity: big.NewInt(20)}
+	assert.NoError(t, account.SetEquityState(equity1.AssetId, equity1))
+	assert.NoError(t, account.SetEquityState(equity2.AssetId, equity2))
+	assets, err = account.GetAssetCodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.Asset{asset2, asset1}, assets)
+	equities, err := account.GetEquities()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.AssetEquity{equity2, equity1}, equities)
+
+	// in trie
+	account.SetVersion(AssetCodeLog, 1, 3)
+	account.SetVersion(EquityLog, 1, 3)
+	assert.NoError(t, account.Finalise())
+	assert.NoError(t, account.Save())
+	account2 := loadAccount(db, defaultAccounts[0].Address)
+	account2.SetAssetCodeRoot(account.GetAssetCodeRoot())
+	account2.SetEquityRoot(account.GetEquityRoot())
+	assets, err = account2.GetAssetCodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.Asset{asset2, asset1}, assets)
+	equities, err = account2.GetEquities()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.AssetEquity{equity2, equity1}, equities)
+
+	// the cached value overrides the trie, and the empty value is removed
+	equity3 := &types.AssetEquity{AssetCode: h(1), AssetId: h(20), Equity: big.NewInt(30)}
+	assert.NoError(t, account2.SetEquityState(equity3.AssetId, equity3))
+	assert.NoError(t, account2.SetEquityState(equity2.AssetId, nil))
+	equities, err = account2.GetEquities()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.AssetEquity{equity3}, equities)
+
+	// invalid root
+	account2.SetEquityRoot(h(1))
+	_, err = account2.GetEquities()
+	assert.Equal(t, ErrTrieFail, err)
+}
+
 func TestAccount_IsEmpty(t *testing.T) {
 	ClearData()
 	db := newDB()
@@ -71,6 +71,7 @@ func init() {
 	types.RegisterChangeLog(SignerLog, "SignerLog", decodeSigners, decodeEmptyInterface, redoSigner, undoSigner)
 	types.RegisterChangeLog(CandidateLog, "CandidateLog", decodeCandidate, decodeEmptyInterface, redoCandidate, undoCandidate)
 	types.RegisterChangeLog(CandidateStateLog, "CandidateStateLog", decodeString, decodeString, redoCandidateState, undoCandidateState)
+	store.EquityLogType = EquityLog
 }
 
 // IsValuable returns true if the change log contains some data change
@@ -10,15 +10,32 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/store/trie"
 	"math/big"
 	"sort"
+	"sync"
 )
 
 // Trie cache generation limit after which to evict trie nodes from memory.
 const MaxTrieCacheGen = uint16(120)
 
+// MaxHistoryHeight is the max height of block whose state can be rebuilt by NewHistoryManager. The change logs of all blocks before it are redone
+const MaxHistoryHeight = uint32(1000000)
+
 var (
 	ErrRevisionNotExist = errors.New("revision cannot be reverted")
 	ErrNoEvents         = errors.New("the times of pop event is more than push")
 	ErrSnapshotIsBroken = errors.New("the snapshot is broken")
+	ErrHistoryTooOld    = errors.New("the history state is too old to be restored")
+)
+
+// historyCheckpoint is the state rebuilt by NewHistoryManager last time. Rebuilding a state which is not older than it starts from it instead of genesis
+type historyCheckpoint struct {
+	db       protocol.ChainDB
+	block    *types.Block
+	accounts map[common.Address]*Account
+}
+
+var (
+	lastHistory   *historyCheckpoint
+	lastHistoryMu sync.Mutex
 )
 
 // TxsProduct is the product of transaction execution
@@ -43,6 +60,8 @@ type Manager struct {
 
 	processor   *LogProcessor
 	versionTrie *trie.SecureTrie
+	// the accounts of history state are all rebuilt in cache, so the accounts not in cache are empty
+	isHistory bool
 }
 
 // NewManager creates a new Manager. It is used to maintain account changes based on the block environment which specified by blockHash
@@ -66,11 +85,100 @@ func NewManager(blockHash common.Hash, db protocol.ChainDB) *Manager {
 	return manager
 }
 
+// NewHistoryManager creates a Manager whose state is the state after the block specified by blockHash.
+// The stable accounts in db are always the latest, so the state of an old stable block is rebuilt by redoing the change logs
+// from genesis block. It may take a long time, and the Manager must never be saved
+func NewHistoryManager(blockHash common.Hash, db protocol.ChainDB) (*Manager, error) {
+	block, err := db.GetBlockByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	stableBlock, err := db.LoadLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	// the state of unstable block or the latest stable block can be loaded directly
+	if block.Height() >= stableBlock.Height() {
+		return NewManager(blockHash, db), nil
+	}
+	if block.Height() > MaxHistoryHeight {
+		return nil, ErrHistoryTooOld
+	}
+
+	manager := NewManager(common.Hash{}, db)
+	// the accounts in db are the latest, so every account must start from empty
+	manager.isHistory = true
+	start := uint32(0)
+	if checkpoint := loadHistoryCheckpoint(db, block.Height()); checkpoint != nil {
+		for address, account := range checkpoint.accounts {
+			manager.accountCache[address] = NewSafeAccount(manager.processor, account.copy())
+		}
+		start = checkpoint.block.Height() + 1
+	}
+	for height := start; height <= block.Height(); height++ {
+		historyBlock, err := db.GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if err := manager.redo(historyBlock.ChangeLogs); err != nil {
+			return nil, err
+		}
+	}
+	manager.baseBlock = block
+	manager.baseBlockHash = blockHash
+	saveHistoryCheckpoint(db, block, manager.accountCache)
+	return manager, nil
+}
+
+// loadHistoryCheckpoint returns the last rebuilt state if it is in the same chain and not higher than the height
+func loadHistoryCheckpoint(db protocol.ChainDB, height uint32) *historyCheckpoint {
+	lastHistoryMu.Lock()
+	checkpoint := lastHistory
+	lastHistoryMu.Unlock()
+	if checkpoint == nil || checkpoint.db != db || checkpoint.block.Height() > height {
+		return nil
+	}
+	block, err := db.GetBlockByHeight(checkpoint.block.Height())
+	if err != nil || block.Hash() != checkpoint.block.Hash() {
+		return nil
+	}
+	return checkpoint
+}
+
+// saveHistoryCheckpoint keeps a copy of the rebuilt accounts, because the returned Manager may be changed by its user
+func saveHistoryCheckpoint(db protocol.ChainDB, block *types.Block, accounts map[common.Address]*SafeAccount) {
+	checkpoint := &historyCheckpoint{
+		db:       db,
+		block:    block,
+		accounts: make(map[common.Address]*Account, len(accounts)),
+	}
+	for address, account := range accounts {
+		checkpoint.accounts[address] = account.rawAccount.copy()
+	}
+	lastHistoryMu.Lock()
+	lastHistory = checkpoint
+	lastHistoryMu.Unlock()
+}
+
+// redo applies the change logs on the accounts in cache without recording them.
+// The root logs are applied too, so that the tries of the history state can be loaded. The values in cache always take precedence over the tries
+func (am *Manager) redo(logs types.ChangeLogSlice) error {
+	for _, cl := range logs {
+		if err := cl.Redo(am.processor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetAccount loads account from cache or db, or creates a new one if it's not exist.
 func (am *Manager) GetAccount(address common.Address) types.AccountAccessor {
 	cached := am.accountCache[address]
 	if cached == nil {
-		data, _ := am.acctDb.Get(address)
+		var data *types.AccountData
+		if !am.isHistory {
+			data, _ = am.acctDb.Get(address)
+		}
 		account := NewAccount(am.db, address, data)
 		cached = NewSafeAccount(am.processor, account)
 		// cache it
@@ -10,7 +10,11 @@ import (
 )
 
 var (
-	ErrSaveReadOnly = errors.New("can not save a read only account")
+	ErrSaveReadOnly  = errors.New("can not save a read only account")
+	ErrBlockNotExist = errors.New("the block does not exist")
+	// ErrHistoryUnavailable is returned when reading the state of a block older than the latest stable block.
+	// Only the states of the latest stable block and unstable blocks are kept
+	ErrHistoryUnavailable = errors.New("historical state unavailable")
 )
 
 // ReadOnlyAccount is used to block any save action on Account
@@ -35,6 +39,9 @@ type ReadOnlyManager struct {
 	stableOnly   bool // 是否只读稳定account
 	db           protocol.ChainDB
 	acctDb       *store.AccountTrieDB
+	allowHistory bool          // 是否允许重建旧的稳定块的状态
+	history      *Manager      // the rebuilt state of an old stable block
+	header       *types.Header // the header of block which is reset to
 	accountCache map[common.Address]*ReadOnlyAccount
 }
 
@@ -52,16 +59,50 @@ func NewReadOnlyManager(db protocol.ChainDB, stableOnly bool) *ReadOnlyManager {
 	return manager
 }
 
-// Reset clears out all data and switch state to the new block environment. It is not necessary to reset if only use stable accounts data
-func (am *ReadOnlyManager) Reset(blockHash common.Hash) {
+// NewHistoryReadOnlyManager creates a ReadOnlyManager which can be reset to an old stable block. Rebuilding the old state may take a long time,
+// so it should only be used by private APIs
+func NewHistoryReadOnlyManager(db protocol.ChainDB) *ReadOnlyManager {
+	manager := NewReadOnlyManager(db, false)
+	manager.allowHistory = true
+	return manager
+}
+
+// Reset clears out all data and switch state to the new block environment. It is not necessary to reset if only use stable accounts data.
+// The state of the stable block which is older than the latest stable block is not kept. It is rebuilt by NewHistoryManager if the manager is created by
+// NewHistoryReadOnlyManager, otherwise ErrHistoryUnavailable is returned
+func (am *ReadOnlyManager) Reset(blockHash common.Hash) error {
 	exist, err := am.db.IsExistByHash(blockHash)
 	if err != nil || !exist {
-		log.Errorf("Reset ReadOnlyManager to block[%#x] fail: %s", blockHash, err)
-		return
+		log.Errorf("Reset ReadOnlyManager to block[%#x] fail: %v", blockHash, err)
+		return ErrBlockNotExist
 	}
 
-	am.acctDb, _ = am.db.GetActDatabase(blockHash)
+	block, err := am.db.GetBlockByHash(blockHash)
+	if err != nil {
+		return err
+	}
+	am.header = block.Header
+	am.history = nil
 	am.accountCache = make(map[common.Address]*ReadOnlyAccount)
+	am.acctDb, _ = am.db.GetActDatabase(blockHash)
+	if am.stableOnly {
+		return nil
+	}
+	stableBlock, err := am.db.LoadLatestBlock()
+	if err != nil {
+		return err
+	}
+	// the account trie of old stable block is not kept, so rebuild it
+	if block.Height() < stableBlock.Height() {
+		if !am.allowHistory {
+			return ErrHistoryUnavailable
+		}
+		am.history, err = NewHistoryManager(blockHash, am.db)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetAccount
@@ -71,6 +112,12 @@ func (am *ReadOnlyManager) GetAccount(address common.Address) types.AccountAcces
 		return cached
 	}
 
+	if am.history != nil {
+		account := &ReadOnlyAccount{Account: *am.history.getRawAccount(address)}
+		am.accountCache[address] = account
+		return account
+	}
+
 	var data *types.AccountData
 	var err error
 	if am.stableOnly || am.acctDb == nil {
@@ -168,6 +168,44 @@ func TestManager_Reset(t *testing.T) {
 	assert.Empty(t, manager.versionTrie)
 }
 
+func TestNewHistoryManager(t *testing.T) {
+	ClearData()
+	db := newDB()
+	defer db.Close()
+	_, err := db.SetStableBlock(defaultBlockInfos[2].hash)
+	assert.NoError(t, err)
+
+	// the latest stable block is loaded directly
+	manager, err := NewHistoryManager(defaultBlockInfos[2].hash, db)
+	assert.NoError(t, err)
+	assert.False(t, manager.isHistory)
+	assert.Equal(t, false, manager.GetAccount(defaultAccounts[0].Address).IsEmpty())
+
+	// the old state is rebuilt by change logs, and the accounts in db are ignored
+	manager, err = NewHistoryManager(defaultBlockInfos[1].hash, db)
+	assert.NoError(t, err)
+	assert.True(t, manager.isHistory)
+	assert.Equal(t, defaultBlockInfos[1].hash, manager.baseBlockHash)
+	assert.Equal(t, true, manager.GetAccount(defaultAccounts[0].Address).IsEmpty())
+
+	// the rebuilt state is kept, and rebuilding a higher block starts from it
+	_, err = NewHistoryManager(defaultBlockInfos[0].hash, db)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultBlockInfos[0].hash, lastHistory.block.Hash())
+	marker := common.HexToAddress("0x1234")
+	lastHistory.accounts[marker] = NewAccount(db, marker, &types.AccountData{Address: marker, Balance: big.NewInt(5)})
+	manager, err = NewHistoryManager(defaultBlockInfos[1].hash, db)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), manager.GetAccount(marker).GetBalance())
+	// the kept state is not changed by the returned manager
+	manager.GetAccount(marker).SetBalance(big.NewInt(6))
+	assert.Equal(t, big.NewInt(5), lastHistory.accounts[marker].GetBalance())
+	// the lower block is rebuilt from genesis
+	manager, err = NewHistoryManager(defaultBlockInfos[0].hash, db)
+	assert.NoError(t, err)
+	assert.Equal(t, true, manager.GetAccount(marker).IsEmpty())
+}
+
 // saving blocks after the newest block
 func TestManager_Finalise_Save(t *testing.T) {
 	ClearData()
@@ -0,0 +1,389 @@
+package account
+
+import (
+	"bytes"
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/merkle"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+	"github.com/LemoFoundationLtd/lemochain-core/store/trie"
+	"math/big"
+)
+
+var (
+	ErrNotReset            = errors.New("the manager has not been reset to a block")
+	ErrInvalidProof        = errors.New("invalid merkle proof")
+	ErrMissingVersionProof = errors.New("the version proofs must contain all types of change log")
+	ErrChangeLogNotFound   = errors.New("the newest change log is not found in block")
+
+	// the root hash of empty trie
+	emptyRoot = common.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// rootLogTypes maps the root log types to the log types which change the tries. The root logs are not recorded in version trie,
+	// but they are generated by Finalise in the same block with the newest logs which change the tries
+	rootLogTypes = map[types.ChangeLogType][]types.ChangeLogType{
+		StorageRootLog:   {StorageLog},
+		AssetCodeRootLog: {AssetCodeLog, AssetCodeStateLog, AssetCodeTotalSupplyLog},
+		AssetIdRootLog:   {AssetIdLog},
+		EquityRootLog:    {EquityLog},
+	}
+)
+
+// LogRootLoader returns the LogRoot in the trusted header of block at height. It is used to verify the change logs in AccountProof
+type LogRootLoader func(height uint32) (common.Hash, error)
+
+// ProofList is a list of rlp encoded trie nodes. It is used to collect the proof from trie and verify it
+type ProofList []hexutil.Bytes
+
+// Put implements store.Putter
+func (l *ProofList) Put(flg uint32, key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+// Get finds the trie node by its hash. It implements store.DatabaseReader
+func (l ProofList) Get(flg uint32, key []byte) ([]byte, error) {
+	for _, node := range l {
+		if bytes.Equal(crypto.Keccak256(node), key) {
+			return node, nil
+		}
+	}
+	return nil, store.ErrNotExist
+}
+
+// Has implements store.DatabaseReader
+func (l ProofList) Has(flg uint32, key []byte) (bool, error) {
+	node, _ := l.Get(flg, key)
+	return node != nil, nil
+}
+
+//go:generate gencodec -type VersionProof --field-override versionProofMarshaling -out gen_version_proof_json.go
+
+// VersionProof proves the newest version of a type of change logs in account by the version trie in block header. The version is 0 and
+// the trie proof proves the absence if the account has never been changed by this type of log.
+// Otherwise the newest change log is proven by the LogRoot of the block which contains it. The newest root log is proven too though it has no version
+type VersionProof struct {
+	LogType   types.ChangeLogType `json:"type" gencodec:"required"`
+	Version   uint32              `json:"version" gencodec:"required"`
+	Proof     ProofList           `json:"proof" gencodec:"required"`
+	ChangeLog hexutil.Bytes       `json:"changeLog"` // rlp encoded, so that the hash of it can be computed
+	LogProof  []merkle.MerkleNode `json:"changeLogProof"`
+}
+
+type versionProofMarshaling struct {
+	LogType hexutil.Uint32
+	Version hexutil.Uint32
+}
+
+//go:generate gencodec -type StorageProof --field-override storageProofMarshaling -out gen_storage_proof_json.go
+
+// StorageProof proves a value in contract storage by the storage trie of account
+type StorageProof struct {
+	Key   common.Hash `json:"key" gencodec:"required"`
+	Value []byte      `json:"value" gencodec:"required"`
+	Proof ProofList   `json:"proof" gencodec:"required"`
+}
+
+type storageProofMarshaling struct {
+	Value hexutil.Bytes
+}
+
+// AccountProof contains the merkle proofs of an account on the state of a block
+type AccountProof struct {
+	VersionRoot   common.Hash        `json:"versionRoot" gencodec:"required"`
+	Account       *types.AccountData `json:"account" gencodec:"required"`
+	VersionProofs []*VersionProof    `json:"versionProof" gencodec:"required"`
+	StorageProofs []*StorageProof    `json:"storageProof" gencodec:"required"`
+}
+
+// GetProof returns the merkle proofs of the account versions and storage values on the state of the block which the manager is reset to.
+// There is a version proof for every type of change log
+func (am *ReadOnlyManager) GetProof(address common.Address, storageKeys []common.Hash) (*AccountProof, error) {
+	if am.header == nil {
+		return nil, ErrNotReset
+	}
+	account := am.GetAccount(address).(*ReadOnlyAccount)
+	result := &AccountProof{
+		VersionRoot:   am.header.VersionRoot,
+		Account:       account.data,
+		VersionProofs: make([]*VersionProof, 0, LOG_TYPE_STOP-BalanceLog),
+		StorageProofs: make([]*StorageProof, 0, len(storageKeys)),
+	}
+
+	versionTrie, err := trie.NewSecure(am.header.VersionRoot, am.db.GetTrieDatabase(), MaxTrieCacheGen)
+	if err != nil {
+		return nil, err
+	}
+	for logType := BalanceLog; logType < LOG_TYPE_STOP; logType++ {
+		key := versionTrieKey(address, logType)
+		value, err := versionTrie.TryGet(key)
+		if err != nil && err != store.ErrNotExist {
+			return nil, err
+		}
+		// prove the absence if the account has never changed by this type of log
+		proof := make(ProofList, 0)
+		if err := versionTrie.Prove(key, 0, &proof); err != nil {
+			return nil, err
+		}
+		versionProof := &VersionProof{
+			LogType: logType,
+			Version: uint32(new(big.Int).SetBytes(value).Uint64()),
+			Proof:   proof,
+		}
+		if len(value) != 0 {
+			if err := am.proveNewestLog(versionProof, address, account.data.NewestRecords[logType].Height); err != nil {
+				return nil, err
+			}
+		} else if height, ok := rootLogHeight(account.data, logType); ok {
+			if err := am.proveNewestLog(versionProof, address, height); err != nil {
+				return nil, err
+			}
+		}
+		result.VersionProofs = append(result.VersionProofs, versionProof)
+	}
+
+	storageTrie, err := account.storage.GetTrie(account.data.StorageRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range storageKeys {
+		value, err := storageTrie.TryGet(key[:])
+		if err != nil && err != store.ErrNotExist {
+			return nil, err
+		}
+		proof := make(ProofList, 0)
+		if err := storageTrie.Prove(key[:], 0, &proof); err != nil {
+			return nil, err
+		}
+		result.StorageProofs = append(result.StorageProofs, &StorageProof{Key: key, Value: value, Proof: proof})
+	}
+	return result, nil
+}
+
+// proveNewestLog finds the newest change log of the version proof in the block at height, and fills the merkle proof of it.
+// The version is not checked for root logs
+func (am *ReadOnlyManager) proveNewestLog(versionProof *VersionProof, address common.Address, height uint32) error {
+	block, err := am.db.GetUnConfirmByHeight(height, am.header.Hash())
+	if err != nil {
+		if block, err = am.db.GetBlockByHeight(height); err != nil {
+			return err
+		}
+	}
+	for i, cl := range block.ChangeLogs {
+		if !isNewestLog(cl, address, versionProof) {
+			continue
+		}
+		if versionProof.LogProof, err = block.ChangeLogs.MerkleProof(i); err != nil {
+			return err
+		}
+		versionProof.ChangeLog, err = rlp.EncodeToBytes(cl)
+		return err
+	}
+	return ErrChangeLogNotFound
+}
+
+// VerifyAccountProof checks the account proof by the version root from a trusted block header, and the LogRoots from trusted block headers:
+//  1. There must be a version proof for every type of change log, which proves the version in version trie or proves it absent
+//  2. The versions must match the NewestRecords in account, and the newest change logs are proven by the LogRoots of the blocks at the recorded heights.
+//     The newest root logs are proven by the blocks which contain the newest logs changing the tries
+//  3. The account fields must match the values in the newest change logs, or be empty if they have never been changed
+//  4. The storage proofs are checked by the proven StorageRoot
+func VerifyAccountProof(versionRoot common.Hash, proof *AccountProof, loadLogRoot LogRootLoader) error {
+	if proof == nil || proof.Account == nil || proof.VersionRoot != versionRoot {
+		return ErrInvalidProof
+	}
+	account := proof.Account
+	if len(proof.VersionProofs) != int(LOG_TYPE_STOP-BalanceLog) {
+		return ErrMissingVersionProof
+	}
+	logs := make(map[types.ChangeLogType]*types.ChangeLog)
+	recordCount := 0
+	for i, versionProof := range proof.VersionProofs {
+		// the proofs are sorted by log type
+		if versionProof.LogType != BalanceLog+types.ChangeLogType(i) {
+			return ErrMissingVersionProof
+		}
+		value, err := trie.VerifySecureProof(versionRoot, versionTrieKey(account.Address, versionProof.LogType), versionProof.Proof)
+		if err != nil {
+			return err
+		}
+		record, ok := account.NewestRecords[versionProof.LogType]
+		if len(value) == 0 {
+			if ok || versionProof.Version != 0 {
+				return ErrInvalidProof
+			}
+			if record.Height, ok = rootLogHeight(account, versionProof.LogType); !ok {
+				continue
+			}
+		} else {
+			if !ok || record.Version != versionProof.Version || new(big.Int).SetBytes(value).Uint64() != uint64(versionProof.Version) {
+				return ErrInvalidProof
+			}
+			recordCount++
+		}
+		cl, err := verifyNewestLog(account.Address, versionProof, record.Height, loadLogRoot)
+		if err != nil {
+			return err
+		}
+		logs[versionProof.LogType] = cl
+	}
+	if len(account.NewestRecords) != recordCount {
+		return ErrInvalidProof
+	}
+	if err := verifyAccountFields(account, logs); err != nil {
+		return err
+	}
+
+	for _, storageProof := range proof.StorageProofs {
+		value, err := trie.VerifySecureProof(account.StorageRoot, storageProof.Key[:], storageProof.Proof)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(value, storageProof.Value) {
+			return ErrInvalidProof
+		}
+	}
+	return nil
+}
+
+// verifyNewestLog decodes the newest change log in version proof, and checks it by the LogRoot of the block at height
+func verifyNewestLog(address common.Address, versionProof *VersionProof, height uint32, loadLogRoot LogRootLoader) (*types.ChangeLog, error) {
+	cl := new(types.ChangeLog)
+	if err := rlp.DecodeBytes(versionProof.ChangeLog, cl); err != nil {
+		return nil, ErrInvalidProof
+	}
+	if !isNewestLog(cl, address, versionProof) {
+		return nil, ErrInvalidProof
+	}
+	logRoot, err := loadLogRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	if !merkle.Verify(cl.Hash(), logRoot, versionProof.LogProof) {
+		return nil, ErrInvalidProof
+	}
+	return cl, nil
+}
+
+// verifyAccountFields checks the account fields by the new values in the newest change logs.
+// The candidate profile keys which are changed by CandidateStateLog can't be proven, because only the newest CandidateStateLog is in the proof
+func verifyAccountFields(account *types.AccountData, logs map[types.ChangeLogType]*types.ChangeLog) error {
+	var (
+		balance   big.Int
+		votes     big.Int
+		codeHash  = common.Sha3Nil
+		roots     = make(map[types.ChangeLogType]common.Hash)
+		voteFor   common.Address
+		signers   types.Signers
+		profile   types.Profile
+		converted = true
+	)
+	for logType, cl := range logs {
+		var ok bool
+		switch logType {
+		case BalanceLog:
+			balance, ok = cl.NewVal.(big.Int)
+		case VotesLog:
+			votes, ok = cl.NewVal.(big.Int)
+		case CodeLog:
+			var code types.Code
+			code, ok = cl.NewVal.(types.Code)
+			codeHash = crypto.Keccak256Hash(code)
+		case StorageRootLog, AssetCodeRootLog, AssetIdRootLog, EquityRootLog:
+			roots[logType], ok = cl.NewVal.(common.Hash)
+		case VoteForLog:
+			voteFor, ok = cl.NewVal.(common.Address)
+		case SignerLog:
+			signers, ok = cl.NewVal.(types.Signers)
+			// the empty signers is decoded as nil
+			ok = ok || cl.NewVal == nil
+		case CandidateLog:
+			var p *types.Profile
+			if p, ok = cl.NewVal.(*types.Profile); ok {
+				profile = *p
+			}
+		default:
+			// the other logs change a part of account, such as a storage value. They are proven by the roots
+			ok = true
+		}
+		converted = converted && ok
+	}
+	if !converted {
+		return ErrInvalidProof
+	}
+
+	accountVotes := account.Candidate.Votes
+	if accountVotes == nil {
+		accountVotes = new(big.Int)
+	}
+	if account.Balance == nil || account.Balance.Cmp(&balance) != 0 || accountVotes.Cmp(&votes) != 0 ||
+		!sameRoot(account.CodeHash, codeHash) ||
+		!sameRoot(account.StorageRoot, roots[StorageRootLog]) ||
+		!sameRoot(account.AssetCodeRoot, roots[AssetCodeRootLog]) ||
+		!sameRoot(account.AssetIdRoot, roots[AssetIdRootLog]) ||
+		!sameRoot(account.EquityRoot, roots[EquityRootLog]) ||
+		account.VoteFor != voteFor ||
+		len(account.Signers) != len(signers) {
+		return ErrInvalidProof
+	}
+	for i, signer := range signers {
+		if account.Signers[i] != signer {
+			return ErrInvalidProof
+		}
+	}
+	_, hasStateLog := logs[CandidateStateLog]
+	for key := range mergeProfileKeys(account.Candidate.Profile, profile) {
+		if hasStateLog && (key == types.CandidateKeyIsCandidate || key == types.CandidateKeyDepositAmount) {
+			continue
+		}
+		actual, ok1 := account.Candidate.Profile[key]
+		expected, ok2 := profile[key]
+		if ok1 != ok2 || actual != expected {
+			return ErrInvalidProof
+		}
+	}
+	return nil
+}
+
+// isNewestLog checks if the change log is the one which the version proof is for. The version of root log is always 0 in version proof
+func isNewestLog(cl *types.ChangeLog, address common.Address, versionProof *VersionProof) bool {
+	return cl.Address == address && cl.LogType == versionProof.LogType && (versionProof.Version == 0 || cl.Version == versionProof.Version)
+}
+
+// rootLogHeight returns the height of block which contains the newest root log. It is the newest height of the logs which change the trie
+func rootLogHeight(account *types.AccountData, logType types.ChangeLogType) (uint32, bool) {
+	var (
+		height uint32
+		found  bool
+	)
+	for _, trieLogType := range rootLogTypes[logType] {
+		if record, ok := account.NewestRecords[trieLogType]; ok && (!found || record.Height > height) {
+			height = record.Height
+			found = true
+		}
+	}
+	return height, found
+}
+
+// sameRoot checks if the two roots are same. All kinds of empty roots are treated as same
+func sameRoot(a, b common.Hash) bool {
+	isEmpty := func(hash common.Hash) bool {
+		return isEmptyHash(hash) || hash == emptyRoot
+	}
+	return a == b || (isEmpty(a) && isEmpty(b))
+}
+
+func mergeProfileKeys(a, b types.Profile) map[string]struct{} {
+	result := make(map[string]struct{}, len(a)+len(b))
+	for key := range a {
+		result[key] = struct{}{}
+	}
+	for key := range b {
+		result[key] = struct{}{}
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package account
+
+import (
+	"encoding/json"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// saveProofTestBlock saves a block after the newest block. The balance and storage of the account are changed in it
+func saveProofTestBlock(t *testing.T, db interface {
+	SetBlock(hash common.Hash, block *types.Block) error
+}, manager *Manager, address common.Address) *types.Block {
+	account := manager.GetAccount(address)
+	account.SetBalance(big.NewInt(100))
+	assert.NoError(t, account.SetStorageState(defaultStorage[0].key, defaultStorage[0].value))
+	assert.NoError(t, account.SetStorageState(defaultStorage[1].key, defaultStorage[1].value))
+	assert.NoError(t, manager.Finalise())
+	logs := manager.GetChangeLogs()
+	block := &types.Block{ChangeLogs: logs}
+	block.SetHeader(&types.Header{
+		ParentHash:  newestBlock.Hash(),
+		Height:      newestBlock.Height() + 1,
+		VersionRoot: manager.GetVersionRoot(),
+		LogRoot:     logs.MerkleRootSha(),
+	})
+	assert.NoError(t, db.SetBlock(block.Hash(), block))
+	assert.NoError(t, manager.Save(block.Hash()))
+	return block
+}
+
+func TestReadOnlyManager_GetProof(t *testing.T) {
+	ClearData()
+	db := newDB()
+	defer db.Close()
+
+	address := common.HexToAddress("0x1234")
+	block := saveProofTestBlock(t, db, NewManager(newestBlock.Hash(), db), address)
+	loadLogRoot := func(height uint32) (common.Hash, error) {
+		if height != block.Height() {
+			return common.Hash{}, store.ErrNotExist
+		}
+		return block.LogRoot(), nil
+	}
+
+	manager := NewReadOnlyManager(db, false)
+	_, err := manager.GetProof(address, nil)
+	assert.Equal(t, ErrNotReset, err)
+	assert.NoError(t, manager.Reset(block.Hash()))
+
+	absentKey := k(1)
+	getProof := func() *AccountProof {
+		proof, err := manager.GetProof(address, []common.Hash{defaultStorage[0].key, defaultStorage[1].key, absentKey})
+		assert.NoError(t, err)
+		return proof
+	}
+	proof := getProof()
+	assert.Equal(t, block.VersionRoot(), proof.VersionRoot)
+	assert.Equal(t, big.NewInt(100), proof.Account.Balance)
+	assert.Equal(t, int(LOG_TYPE_STOP-BalanceLog), len(proof.VersionProofs))
+	assert.Equal(t, BalanceLog, proof.VersionProofs[0].LogType)
+	assert.Equal(t, uint32(1), proof.VersionProofs[0].Version)
+	balanceLog := new(types.ChangeLog)
+	assert.NoError(t, rlp.DecodeBytes(proof.VersionProofs[0].ChangeLog, balanceLog))
+	assert.Equal(t, *big.NewInt(100), balanceLog.NewVal)
+	assert.NotEmpty(t, proof.VersionProofs[StorageRootLog-BalanceLog].ChangeLog)
+	// never changed by code log
+	assert.Equal(t, uint32(0), proof.VersionProofs[CodeLog-BalanceLog].Version)
+	assert.Empty(t, proof.VersionProofs[CodeLog-BalanceLog].ChangeLog)
+	assert.Equal(t, 3, len(proof.StorageProofs))
+	assert.Equal(t, defaultStorage[0].value, []byte(proof.StorageProofs[0].Value))
+	assert.Equal(t, defaultStorage[1].value, []byte(proof.StorageProofs[1].Value))
+	assert.Empty(t, proof.StorageProofs[2].Value)
+	assert.NoError(t, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+
+	// json
+	data, err := json.Marshal(proof)
+	assert.NoError(t, err)
+	decoded := new(AccountProof)
+	assert.NoError(t, json.Unmarshal(data, decoded))
+	assert.NoError(t, VerifyAccountProof(block.VersionRoot(), decoded, loadLogRoot))
+
+	// wrong root
+	assert.Error(t, VerifyAccountProof(common.HexToHash("0x1234"), proof, loadLogRoot))
+	// untrusted block
+	assert.Equal(t, store.ErrNotExist, VerifyAccountProof(block.VersionRoot(), proof, func(height uint32) (common.Hash, error) {
+		return common.Hash{}, store.ErrNotExist
+	}))
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, func(height uint32) (common.Hash, error) {
+		return newestBlock.LogRoot(), nil
+	}))
+	// fake version
+	proof.VersionProofs[0].Version = 2
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	// fake storage value
+	proof = getProof()
+	proof.StorageProofs[0].Value = []byte{12}
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	// proof of absent storage value
+	proof = getProof()
+	proof.StorageProofs[2].Value = []byte{1}
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+
+	// tampered account
+	proof = getProof()
+	proof.Account.Balance = big.NewInt(101)
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	proof = getProof()
+	proof.Account.StorageRoot = defaultAccounts[0].StorageRoot
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	proof = getProof()
+	proof.Account.CodeHash = defaultAccounts[0].CodeHash
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	proof = getProof()
+	proof.Account.NewestRecords[CodeLog] = types.VersionRecord{Version: 1, Height: block.Height()}
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	// tampered change log
+	proof = getProof()
+	fakeLog, err := rlp.EncodeToBytes(&types.ChangeLog{LogType: BalanceLog, Address: address, Version: 1, NewVal: *big.NewInt(101)})
+	assert.NoError(t, err)
+	proof.VersionProofs[0].ChangeLog = fakeLog
+	proof.Account.Balance = big.NewInt(101)
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+
+	// omitted version proofs
+	proof = getProof()
+	proof.VersionProofs = nil
+	assert.Equal(t, ErrMissingVersionProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	proof = getProof()
+	proof.VersionProofs = proof.VersionProofs[1:]
+	assert.Equal(t, ErrMissingVersionProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	proof = getProof()
+	proof.VersionProofs[CodeLog-BalanceLog] = proof.VersionProofs[0]
+	assert.Equal(t, ErrMissingVersionProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+
+	// not exist account
+	proof, err = manager.GetProof(common.HexToAddress("0x1"), []common.Hash{absentKey})
+	assert.NoError(t, err)
+	for _, versionProof := range proof.VersionProofs {
+		assert.Equal(t, uint32(0), versionProof.Version)
+	}
+	assert.Empty(t, proof.StorageProofs[0].Value)
+	assert.NoError(t, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+	// claim the balance of not exist account
+	proof.Account.Balance = big.NewInt(1)
+	assert.Equal(t, ErrInvalidProof, VerifyAccountProof(block.VersionRoot(), proof, loadLogRoot))
+}
@@ -62,6 +62,10 @@ func (a *SafeAccount) GetAssetCode(code common.Hash) (*types.Asset, error) {
 	return a.rawAccount.GetAssetCode(code)
 }
 
+func (a *SafeAccount) GetAssetCodes() ([]*types.Asset, error) {
+	return a.rawAccount.GetAssetCodes()
+}
+
 func (a *SafeAccount) SetAssetCode(code common.Hash, asset *types.Asset) error {
 	newLog, err := NewAssetCodeLog(a.GetAddress(), a.processor, code, asset)
 	if err != nil {
@@ -212,6 +216,10 @@ func (a *SafeAccount) GetEquityState(id common.Hash) (*types.AssetEquity, error)
 	return a.rawAccount.GetEquityState(id)
 }
 
+func (a *SafeAccount) GetEquities() ([]*types.AssetEquity, error) {
+	return a.rawAccount.GetEquities()
+}
+
 func (a *SafeAccount) SetEquityState(id common.Hash, equity *types.AssetEquity) error {
 	newLog, err := NewEquityLog(a.GetAddress(), a.processor, id, equity)
 	if err != nil {
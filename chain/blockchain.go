@@ -121,6 +121,8 @@ func (bc *BlockChain) runFeedTranspondLoop() {
 	currentSub := bc.engine.SubscribeCurrent(currentCh)
 	stableCh := make(chan *types.Block)
 	stableSub := bc.engine.SubscribeStable(stableCh)
+	switchForkCh := make(chan *consensus.SwitchForkEvent)
+	switchForkSub := bc.engine.SubscribeSwitchFork(switchForkCh)
 	confirmCh := make(chan *network.BlockConfirmData)
 	confirmSub := bc.engine.SubscribeConfirm(confirmCh)
 	fetchConfirmCh := make(chan []network.GetConfirmInfo)
@@ -131,6 +133,8 @@ func (bc *BlockChain) runFeedTranspondLoop() {
 			go subscribe.Send(subscribe.NewCurrentBlock, block)
 		case block := <-stableCh:
 			go subscribe.Send(subscribe.NewStableBlock, block)
+		case event := <-switchForkCh:
+			go subscribe.Send(subscribe.SwitchFork, event)
 		case confirm := <-confirmCh:
 			go subscribe.Send(subscribe.NewConfirm, confirm)
 		case confirmsInfo := <-fetchConfirmCh:
@@ -138,6 +142,7 @@ func (bc *BlockChain) runFeedTranspondLoop() {
 		case <-bc.quitCh:
 			currentSub.Unsubscribe()
 			stableSub.Unsubscribe()
+			switchForkSub.Unsubscribe()
 			confirmSub.Unsubscribe()
 			fetchConfirmSub.Unsubscribe()
 			return
@@ -214,6 +219,31 @@ func (bc *BlockChain) StableBlock() *types.Block {
 	return bc.engine.StableBlock()
 }
 
+// SubscribeCurrent subscribe the current block update notification. The blocks may be not continuous
+func (bc *BlockChain) SubscribeCurrent(ch chan *types.Block) subscribe.Subscription {
+	return bc.engine.SubscribeCurrent(ch)
+}
+
+// SubscribeStable subscribe the stable block update notification. The blocks may be not continuous
+func (bc *BlockChain) SubscribeStable(ch chan *types.Block) subscribe.Subscription {
+	return bc.engine.SubscribeStable(ch)
+}
+
+// SubscribeSwitchFork subscribe the notification of current block switching to another fork
+func (bc *BlockChain) SubscribeSwitchFork(ch chan *consensus.SwitchForkEvent) subscribe.Subscription {
+	return bc.engine.SubscribeSwitchFork(ch)
+}
+
+// GetForks returns every unstable branch and marks the branch which contains the current block
+func (bc *BlockChain) GetForks(currentHash common.Hash) []*store.Fork {
+	return bc.db.GetForks(currentHash)
+}
+
+// GetConfirmSigners returns the deputies who have signed the block and the deputies who haven't
+func (bc *BlockChain) GetConfirmSigners(block *types.Block) (types.DeputyNodes, types.DeputyNodes, error) {
+	return consensus.GetConfirmSigners(block, bc.dm)
+}
+
 func (bc *BlockChain) MineBlock(txProcessTimeout int64) {
 	if atomic.LoadInt32(&bc.stopped) != 0 {
 		return
@@ -252,6 +282,22 @@ func (bc *BlockChain) GetCandidatesTop(hash common.Hash) []*store.Candidate {
 	return bc.db.GetCandidatesTop(hash)
 }
 
+// LoadStableTopCandidates returns the candidates which would be the deputies if the snapshot is taken on the stable block.
+// Only the stable accounts are read, so it won't disturb the account manager which is mining
+func (bc *BlockChain) LoadStableTopCandidates() types.DeputyNodes {
+	return consensus.LoadTopCandidates(bc.db, bc.dm.DeputyCount, bc.StableBlock().Hash(), bc.am.GetCanonicalAccount)
+}
+
+// GetCandidatesPage returns the addresses of candidates by page. They are sorted by register order
+func (bc *BlockChain) GetCandidatesPage(index int, size int) ([]common.Address, uint32, error) {
+	return bc.db.GetCandidatesPage(index, size)
+}
+
+// GetAllCandidates returns the addresses of all candidates
+func (bc *BlockChain) GetAllCandidates() ([]common.Address, error) {
+	return bc.db.GetAllCandidates()
+}
+
 // Stop stop block chain
 func (bc *BlockChain) Stop() {
 	if !atomic.CompareAndSwapInt32(&bc.stopped, 0, 1) {
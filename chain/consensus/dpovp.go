@@ -46,6 +46,7 @@ type DPoVP struct {
 	// all dpovp events are here
 	stableFeed        subscribe.Feed // stable block change event
 	currentFeed       subscribe.Feed // head block change event
+	switchForkFeed    subscribe.Feed // head block switch to another fork event
 	confirmFeed       subscribe.Feed // new confirm event
 	fetchConfirmsFeed subscribe.Feed // fetch confirms event
 }
@@ -93,6 +94,11 @@ func (dp *DPoVP) SubscribeCurrent(ch chan *types.Block) subscribe.Subscription {
 	return dp.currentFeed.Subscribe(ch)
 }
 
+// SubscribeSwitchFork subscribe the notification of current block switching to another fork
+func (dp *DPoVP) SubscribeSwitchFork(ch chan *SwitchForkEvent) subscribe.Subscription {
+	return dp.switchForkFeed.Subscribe(ch)
+}
+
 // SubscribeConfirm subscribe the new confirm notification
 func (dp *DPoVP) SubscribeConfirm(ch chan *network.BlockConfirmData) subscribe.Subscription {
 	return dp.confirmFeed.Subscribe(ch)
@@ -121,6 +127,7 @@ func (dp *DPoVP) MineBlock(txProcessTimeout int64) (*types.Block, error) {
 		return nil, err
 	}
 
+	dp.txPool.Prune(header.Time)
 	txs := dp.txPool.Get(header.Time, params.MaxTxsForMiner)
 	log.Debugf("pick %d txs from txPool", len(txs))
 	block, invalidTxs, err := dp.assembler.MineBlock(header, txs, txProcessTimeout)
@@ -129,7 +136,7 @@ func (dp *DPoVP) MineBlock(txProcessTimeout int64) (*types.Block, error) {
 	}
 	log.Info("Mined a new block", "block", block.ShortString(), "txsCount", len(block.Txs))
 	// remove invalid txs from pool
-	dp.txPool.DelInvalidTxs(invalidTxs)
+	dp.txPool.DelInvalidTxs(invalidTxs, dp.processor.InvalidTxErrs())
 
 	// save
 	if err = dp.saveNewBlock(block); err != nil {
@@ -204,6 +211,7 @@ func (dp *DPoVP) saveNewBlock(block *types.Block) error {
 	newCurrent := dp.forkManager.UpdateFork(block, dp.StableBlock())
 	if newCurrent != nil {
 		go dp.currentFeed.Send(newCurrent)
+		dp.notifySwitchFork(oldCurrent, newCurrent)
 	}
 	// To confirm a block from another fork, we need a height distance that more than 2/3 deputies count.
 	// But the new current's height is 2/3 deputies count bigger at most, so we don't need to try to confirm the new current block here
@@ -225,6 +233,10 @@ func (dp *DPoVP) saveToStore(block *types.Block) error {
 		log.Error("Insert block to cache fail", "block", block.ShortString())
 		return ErrSaveBlock
 	}
+	// the receipts are only used for query, so it should not break the block saving
+	if err := dp.db.SetReceipts(hash, dp.processor.Receipts()); err != nil {
+		log.Error("Save receipts fail", "block", block.ShortString(), "err", err)
+	}
 	log.Info("Save block to store", "block", block.ShortString(), "time", block.Time(), "parent", block.ParentHash())
 
 	if err := dp.am.Save(hash); err != nil {
@@ -324,6 +336,30 @@ func (dp *DPoVP) logCurrentChange(oldCurrent *types.Block) {
 	}
 }
 
+// notifySwitchFork sends switch fork event if the new current block is not the descendant of old current block
+func (dp *DPoVP) notifySwitchFork(oldCurrent, newCurrent *types.Block) {
+	if dp.isDescendant(oldCurrent, newCurrent) {
+		return
+	}
+	go dp.switchForkFeed.Send(&SwitchForkEvent{OldHead: oldCurrent.Header, NewHead: newCurrent.Header})
+}
+
+// isDescendant walks back from the block to the height of ancestor, and checks whether it reaches the ancestor
+func (dp *DPoVP) isDescendant(ancestor, block *types.Block) bool {
+	for block.Height() > ancestor.Height() {
+		if block.ParentHash() == ancestor.Hash() {
+			return true
+		}
+		parent, err := dp.db.GetBlockByHash(block.ParentHash())
+		if err != nil {
+			log.Errorf("Load block fail when check fork. hash: %s, err: %v", block.ParentHash().Hex(), err)
+			return false
+		}
+		block = parent
+	}
+	return block.Hash() == ancestor.Hash()
+}
+
 // isIgnorableBlock check the block is exist or not
 func (dp *DPoVP) isIgnorableBlock(block *types.Block) bool {
 	if has, _ := dp.db.IsExistByHash(block.Hash()); has {
@@ -389,6 +425,7 @@ func (dp *DPoVP) InsertConfirm(info *network.BlockConfirmData) error {
 	newCurrent := dp.forkManager.UpdateForkForConfirm(dp.StableBlock())
 	if newCurrent != nil {
 		go dp.currentFeed.Send(newCurrent)
+		dp.notifySwitchFork(oldCurrent, newCurrent)
 		dp.logCurrentChange(oldCurrent)
 	}
 
@@ -425,14 +462,19 @@ func (dp *DPoVP) insertConfirms(height uint32, blockHash common.Hash, sigList []
 
 // SnapshotDeputyNodes get next epoch deputy nodes for snapshot block
 func (dp *DPoVP) LoadTopCandidates(blockHash common.Hash) types.DeputyNodes {
-	result := make(types.DeputyNodes, 0, dp.dm.DeputyCount)
-	list := dp.db.GetCandidatesTop(blockHash)
-	if len(list) > dp.dm.DeputyCount {
-		list = list[:dp.dm.DeputyCount]
+	return LoadTopCandidates(dp.db, dp.dm.DeputyCount, blockHash, dp.am.GetAccount)
+}
+
+// LoadTopCandidates loads the top candidates on the block, and reads their votes and node ids from the accounts returned by getAccount
+func LoadTopCandidates(db protocol.ChainDB, deputyCount int, blockHash common.Hash, getAccount func(common.Address) types.AccountAccessor) types.DeputyNodes {
+	result := make(types.DeputyNodes, 0, deputyCount)
+	list := db.GetCandidatesTop(blockHash)
+	if len(list) > deputyCount {
+		list = list[:deputyCount]
 	}
 
 	for i, n := range list {
-		acc := dp.am.GetAccount(n.GetAddress())
+		acc := getAccount(n.GetAddress())
 		candidate := acc.GetCandidate()
 		strID := candidate[types.CandidateKeyNodeID]
 		dn := types.NewDeputyNode(acc.GetVotes(), uint32(i), n.GetAddress(), strID)
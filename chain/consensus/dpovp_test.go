@@ -231,3 +231,53 @@ func TestDPoVP_InsertBlock2(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, newBlock2.Hash(), dp.CurrentBlock().Hash())
 }
+
+func TestDPoVP_notifySwitchFork(t *testing.T) {
+	dp, _ := newTestDPoVP(3)
+	defer dp.db.Close()
+
+	ch := make(chan *SwitchForkEvent, 1)
+	sub := dp.SubscribeSwitchFork(ch)
+	defer sub.Unsubscribe()
+
+	parent := dp.CurrentBlock()
+	block1 := &types.Block{Header: &types.Header{ParentHash: parent.Hash(), Height: parent.Height() + 1, Time: 1}}
+	block1q := &types.Block{Header: &types.Header{ParentHash: parent.Hash(), Height: parent.Height() + 1, Time: 2}}
+	block2 := &types.Block{Header: &types.Header{ParentHash: block1.Hash(), Height: parent.Height() + 2}}
+	block3 := &types.Block{Header: &types.Header{ParentHash: block2.Hash(), Height: parent.Height() + 3}}
+	assert.NoError(t, dp.db.SetBlock(block1.Hash(), block1))
+	assert.NoError(t, dp.db.SetBlock(block2.Hash(), block2))
+
+	// same block or descendant block
+	dp.notifySwitchFork(parent, parent)
+	dp.notifySwitchFork(parent, block1)
+	dp.notifySwitchFork(block1, block2)
+	dp.notifySwitchFork(parent, block3)
+	select {
+	case <-ch:
+		t.Fatal("should not switch fork")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// another fork
+	dp.notifySwitchFork(block2, block1q)
+	select {
+	case event := <-ch:
+		assert.Equal(t, block2.Hash(), event.OldHead.Hash())
+		assert.Equal(t, block1q.Hash(), event.NewHead.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("switch fork event timeout")
+	}
+
+	// deeper block in another fork
+	block2q := &types.Block{Header: &types.Header{ParentHash: block1q.Hash(), Height: parent.Height() + 2}}
+	assert.NoError(t, dp.db.SetBlock(block1q.Hash(), block1q))
+	dp.notifySwitchFork(block1, block2q)
+	select {
+	case event := <-ch:
+		assert.Equal(t, block1.Hash(), event.OldHead.Hash())
+		assert.Equal(t, block2q.Hash(), event.NewHead.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("switch fork event timeout")
+	}
+}
@@ -68,3 +68,42 @@ func IsConfirmEnough(block *types.Block, dm *deputynode.Manager) bool {
 
 	return uint32(singerCount) >= dm.TwoThirdDeputyCount(block.Height())
 }
+
+// GetConfirmSigners returns the deputies who have signed the block (include the miner) and the deputies who haven't signed yet
+func GetConfirmSigners(block *types.Block, dm *deputynode.Manager) (signed types.DeputyNodes, missing types.DeputyNodes, err error) {
+	hash := block.Hash()
+	signedMap := make(map[common.Address]bool)
+	markSigner := func(nodeID []byte) {
+		if deputy := dm.GetDeputyByNodeID(block.Height(), nodeID); deputy != nil {
+			signedMap[deputy.MinerAddress] = true
+		}
+	}
+	// the genesis block has no miner signature
+	if block.Height() > 0 {
+		nodeID, err := block.SignerNodeID()
+		if err != nil {
+			log.Warn("Invalid block signature", "hash", hash.Hex(), "err", err)
+			return nil, nil, ErrInvalidBlock
+		}
+		markSigner(nodeID)
+	}
+	for _, sig := range block.Confirms {
+		nodeID, err := sig.RecoverNodeID(hash)
+		if err != nil {
+			log.Warn("Invalid confirm signature", "hash", hash.Hex(), "sig", common.ToHex(sig[:]), "err", err)
+			return nil, nil, ErrInvalidSignedConfirmInfo
+		}
+		markSigner(nodeID)
+	}
+
+	signed = make(types.DeputyNodes, 0, len(signedMap))
+	missing = make(types.DeputyNodes, 0)
+	for _, deputy := range dm.GetDeputiesByHeight(block.Height()) {
+		if signedMap[deputy.MinerAddress] {
+			signed = append(signed, deputy)
+		} else {
+			missing = append(missing, deputy)
+		}
+	}
+	return signed, missing, nil
+}
@@ -3,6 +3,7 @@ package consensus
 import (
 	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/LemoFoundationLtd/lemochain-core/store"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -146,3 +147,47 @@ func TestIsConfirmEnough(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConfirmSigners(t *testing.T) {
+	dm := initDeputyManager(5)
+	block := &types.Block{Header: &types.Header{Height: 101}}
+	hash := block.Hash()
+	sign := func(index int) types.SignData {
+		sig, err := crypto.Sign(hash[:], testDeputies[index].PrivateKey)
+		assert.NoError(t, err)
+		var signData types.SignData
+		copy(signData[:], sig)
+		return signData
+	}
+	minerSig := sign(0)
+	block.Header.SignData = minerSig[:]
+
+	// only miner
+	signed, missing, err := GetConfirmSigners(block, dm)
+	assert.NoError(t, err)
+	deputies := dm.GetDeputiesByHeight(block.Height())
+	assert.Equal(t, deputies[:1], signed)
+	assert.Equal(t, deputies[1:], missing)
+
+	// the confirm from other node is ignored
+	block.Confirms = []types.SignData{sign(3), sign(2), sign(10)}
+	signed, missing, err = GetConfirmSigners(block, dm)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(signed))
+	assert.Equal(t, testDeputies[0].MinerAddress, signed[0].MinerAddress)
+	assert.Equal(t, testDeputies[2].MinerAddress, signed[1].MinerAddress)
+	assert.Equal(t, testDeputies[3].MinerAddress, signed[2].MinerAddress)
+	assert.Equal(t, 2, len(missing))
+	assert.Equal(t, testDeputies[1].MinerAddress, missing[0].MinerAddress)
+	assert.Equal(t, testDeputies[4].MinerAddress, missing[1].MinerAddress)
+
+	// invalid confirm
+	block.Confirms = []types.SignData{{}}
+	_, _, err = GetConfirmSigners(block, dm)
+	assert.Equal(t, ErrInvalidSignedConfirmInfo, err)
+
+	// invalid miner signature
+	block = &types.Block{Header: &types.Header{Height: 101, SignData: []byte{0x12}}}
+	_, _, err = GetConfirmSigners(block, dm)
+	assert.Equal(t, ErrInvalidBlock, err)
+}
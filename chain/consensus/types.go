@@ -24,6 +24,12 @@ type BlockMaterial struct {
 	Txs          types.Transactions
 }
 
+// SwitchForkEvent is sent when the current block is switched to another fork
+type SwitchForkEvent struct {
+	OldHead *types.Header `json:"oldHead"`
+	NewHead *types.Header `json:"newHead"`
+}
+
 // BlockLoader is the interface of ChainDB
 type BlockLoader interface {
 	IterateUnConfirms(fn func(*types.Block))
@@ -41,7 +47,8 @@ type StableBlockStore interface {
 
 type TxPool interface {
 	Get(time uint32, size int) []*types.Transaction
-	DelInvalidTxs(txs []*types.Transaction)
+	Prune(time uint32)
+	DelInvalidTxs(txs []*types.Transaction, reasons []error)
 	VerifyTxInBlock(block *types.Block) bool
 	RecvBlock(block *types.Block)
 	PruneBlock(block *types.Block)
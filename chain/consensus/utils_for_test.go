@@ -253,7 +253,11 @@ func (txPoolForValidator) Get(time uint32, size int) []*types.Transaction {
 	panic("implement me")
 }
 
-func (txPoolForValidator) DelInvalidTxs(txs []*types.Transaction) {
+func (txPoolForValidator) Prune(time uint32) {
+	panic("implement me")
+}
+
+func (txPoolForValidator) DelInvalidTxs(txs []*types.Transaction, reasons []error) {
 	panic("implement me")
 }
 
@@ -152,6 +152,16 @@ func (m *Manager) GetTermByHeight(height uint32) (*TermRecord, error) {
 	}
 }
 
+// GetTermList returns all saved terms. The last one may be not started yet
+func (m *Manager) GetTermList() []*TermRecord {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	result := make([]*TermRecord, len(m.termList))
+	copy(result, m.termList)
+	return result
+}
+
 // GetDeputiesByHeight 通过height获取对应的节点列表
 func (m *Manager) GetDeputiesByHeight(height uint32) types.DeputyNodes {
 	term, err := m.GetTermByHeight(height)
@@ -80,6 +80,25 @@ func TestNewManager(t *testing.T) {
 	assert.Equal(t, block2.DeputyNodes, m.termList[1].Nodes)
 }
 
+func TestManager_GetTermList(t *testing.T) {
+	m := NewManager(5, testBlockLoader{})
+	assert.Len(t, m.GetTermList(), 0)
+
+	nodes0 := pickNodes(0, 1)
+	m.SaveSnapshot(0, nodes0)
+	nodes1 := pickNodes(2)
+	m.SaveSnapshot(params.TermDuration, nodes1)
+	list := m.GetTermList()
+	assert.Len(t, list, 2)
+	assert.Equal(t, nodes0, list[0].Nodes)
+	assert.Equal(t, uint32(1), list[1].TermIndex)
+	assert.Equal(t, nodes1, list[1].Nodes)
+
+	// the returned list is a copy
+	list[0] = nil
+	assert.NotNil(t, m.GetTermList()[0])
+}
+
 func TestManager_SaveSnapshot(t *testing.T) {
 	m := NewManager(5, testBlockLoader{})
 
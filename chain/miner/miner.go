@@ -7,6 +7,7 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/common/subscribe"
+	"math/big"
 	"sync/atomic"
 	"time"
 )
@@ -24,6 +25,7 @@ type Chain interface {
 
 type TxPool interface {
 	ExistCanPackageTx(time uint32) bool
+	PackingGasPrice(time uint32) *big.Int
 }
 
 type MineInfo struct {
@@ -113,6 +115,11 @@ func (m *Miner) IsMining() bool {
 	return atomic.LoadInt32(&m.mining) == 1
 }
 
+// GetPackingGasPrice returns the least gas price of transactions which could be packed in next block
+func (m *Miner) GetPackingGasPrice() *big.Int {
+	return m.txPool.PackingGasPrice(uint32(time.Now().Unix()))
+}
+
 func (m *Miner) GetMinerAddress() common.Address {
 	// Get self deputy info in the term which next block in
 	minerAddress, _ := m.dm.GetMyMinerAddress(m.chain.CurrentBlock().Height() + 1)
@@ -178,8 +185,8 @@ func (m *Miner) runMineLoop() {
 	}
 }
 
-// getSleepTime get sleep time (millisecond) to seal block, return waitTime and absolute time of block timeout
-func (m *Miner) getSleepTime(mineHeight uint32, distance uint32, parentTime int64, currentTime int64) (int64, int64) {
+// getMineWindow get the next time window (millisecond) in which the deputy with the distance can seal block
+func (m *Miner) getMineWindow(mineHeight uint32, distance uint32, parentTime int64, currentTime int64) (int64, int64) {
 	// 网络传输耗时，即当前时间减去父块区块头中的时间戳
 	passTime := currentTime - parentTime
 	// 可以出块的时间窗口
@@ -189,6 +196,13 @@ func (m *Miner) getSleepTime(mineHeight uint32, distance uint32, parentTime int6
 		// distance == 1表示下一个区块该本节点产生了，也没有超时，windowFrom为0。这时需要确保延迟足够的时间，避免早期交易少时链上全是空块
 		windowFrom = parentTime + m.blockInterval
 	}
+	return windowFrom, windowTo
+}
+
+// getSleepTime get sleep time (millisecond) to seal block, return waitTime and absolute time of block timeout
+func (m *Miner) getSleepTime(mineHeight uint32, distance uint32, parentTime int64, currentTime int64) (int64, int64) {
+	passTime := currentTime - parentTime
+	windowFrom, windowTo := m.getMineWindow(mineHeight, distance, parentTime, currentTime)
 
 	// 等到下个时间窗口
 	waitTime := windowFrom - currentTime
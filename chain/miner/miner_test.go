@@ -3,6 +3,7 @@ package miner
 import (
 	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/LemoFoundationLtd/lemochain-core/store"
@@ -168,3 +169,7 @@ type testTxpool struct {
 func (p *testTxpool) ExistCanPackageTx(time uint32) bool {
 	return p.IsExist
 }
+
+func (p *testTxpool) PackingGasPrice(time uint32) *big.Int {
+	return params.MinGasPrice
+}
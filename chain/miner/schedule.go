@@ -0,0 +1,94 @@
+package miner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+)
+
+//go:generate gencodec -type MineSlot --field-override mineSlotMarshaling -out gen_mine_slot_json.go
+
+// MineSlot is a time window in which the deputy can mine the block. The times are timestamps in millisecond
+type MineSlot struct {
+	Height       uint32         `json:"height" gencodec:"required"`
+	MinerAddress common.Address `json:"minerAddress" gencodec:"required"`
+	WindowFrom   int64          `json:"windowFrom" gencodec:"required"`
+	WindowTo     int64          `json:"windowTo" gencodec:"required"`
+}
+
+type mineSlotMarshaling struct {
+	Height hexutil.Uint32
+}
+
+// GetSchedule returns the next count mine slots after current block, sorted by time.
+// It assumes no new block is coming, so the schedule is changed once a new block is mined
+func (m *Miner) GetSchedule(count int) ([]*MineSlot, error) {
+	current := m.chain.CurrentBlock()
+	return m.getSchedule(current.Height(), current.MinerAddress(), int64(current.Time())*1000, time.Now().UnixNano()/1e6, count)
+}
+
+// GetMyNextSlot returns the next mine slot of this node
+func (m *Miner) GetMyNextSlot() (*MineSlot, error) {
+	current := m.chain.CurrentBlock()
+	minerAddress, ok := m.dm.GetMyMinerAddress(current.Height() + 1)
+	if !ok {
+		return nil, deputynode.ErrNotDeputy
+	}
+	nodeCount := m.dm.GetDeputiesCount(current.Height() + 1)
+	slots, err := m.getSchedule(current.Height(), current.MinerAddress(), int64(current.Time())*1000, time.Now().UnixNano()/1e6, nodeCount)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if slot.MinerAddress == minerAddress {
+			return slot, nil
+		}
+	}
+	return nil, deputynode.ErrNotDeputy
+}
+
+// getSchedule computes the mine slots of all deputies after the parent block, then repeat them by loop until the count is reached
+func (m *Miner) getSchedule(parentHeight uint32, parentMiner common.Address, parentTime int64, currentTime int64, count int) ([]*MineSlot, error) {
+	mineHeight := parentHeight + 1
+	nodeCount := m.dm.GetDeputiesCount(mineHeight)
+	if nodeCount == 0 {
+		return nil, deputynode.ErrNotDeputy
+	}
+
+	slots := make([]*MineSlot, 0, count)
+	for distance := uint32(1); distance <= uint32(nodeCount); distance++ {
+		deputy, err := m.dm.GetDeputyByDistance(mineHeight, parentMiner, distance)
+		if err != nil {
+			return nil, err
+		}
+		windowFrom, windowTo := m.getMineWindow(mineHeight, distance, parentTime, currentTime)
+		slots = append(slots, &MineSlot{
+			Height:       mineHeight,
+			MinerAddress: deputy.MinerAddress,
+			WindowFrom:   windowFrom,
+			WindowTo:     windowTo,
+		})
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].WindowFrom < slots[j].WindowFrom
+	})
+
+	// every deputy get a chance in one loop. The block interval delay only works in the first window
+	oneLoopTime := int64(nodeCount) * m.timeoutTime
+	for i := nodeCount; i < count; i++ {
+		last := slots[i-nodeCount]
+		slots = append(slots, &MineSlot{
+			Height:       mineHeight,
+			MinerAddress: last.MinerAddress,
+			WindowFrom:   last.WindowTo - m.timeoutTime + oneLoopTime,
+			WindowTo:     last.WindowTo + oneLoopTime,
+		})
+	}
+	if len(slots) > count {
+		slots = slots[:count]
+	}
+	return slots, nil
+}
@@ -0,0 +1,74 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiner_getSchedule(t *testing.T) {
+	deputyCount := 3
+	dm := deputynode.NewManager(deputyCount, &testChain{})
+	dm.SaveSnapshot(0, testDeputies[:deputyCount])
+
+	var blockInterval int64 = 1000
+	var mineTimeout int64 = 2000
+	parentTime := int64(time.Now().Unix()) * 1000
+	miner := New(MineConfig{SleepTime: blockInterval, Timeout: mineTimeout}, nil, dm, nil)
+
+	slots, err := miner.getSchedule(1, testDeputies[0].MinerAddress, parentTime, parentTime+10, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []*MineSlot{
+		{Height: 2, MinerAddress: testDeputies[1].MinerAddress, WindowFrom: parentTime + blockInterval, WindowTo: parentTime + mineTimeout},
+		{Height: 2, MinerAddress: testDeputies[2].MinerAddress, WindowFrom: parentTime + mineTimeout, WindowTo: parentTime + mineTimeout*2},
+		{Height: 2, MinerAddress: testDeputies[0].MinerAddress, WindowFrom: parentTime + mineTimeout*2, WindowTo: parentTime + mineTimeout*3},
+		{Height: 2, MinerAddress: testDeputies[1].MinerAddress, WindowFrom: parentTime + mineTimeout*3, WindowTo: parentTime + mineTimeout*4},
+		{Height: 2, MinerAddress: testDeputies[2].MinerAddress, WindowFrom: parentTime + mineTimeout*4, WindowTo: parentTime + mineTimeout*5},
+	}, slots)
+
+	// the first deputy's window is passed
+	slots, err = miner.getSchedule(1, testDeputies[0].MinerAddress, parentTime, parentTime+mineTimeout+10, 2)
+	assert.NoError(t, err)
+	assert.Len(t, slots, 2)
+	assert.Equal(t, testDeputies[2].MinerAddress, slots[0].MinerAddress)
+	assert.Equal(t, testDeputies[0].MinerAddress, slots[1].MinerAddress)
+
+	// no deputy
+	miner = New(MineConfig{SleepTime: blockInterval, Timeout: mineTimeout}, nil, deputynode.NewManager(deputyCount, &testChain{}), nil)
+	_, err = miner.getSchedule(1, testDeputies[0].MinerAddress, parentTime, parentTime+10, 5)
+	assert.Equal(t, deputynode.ErrNotDeputy, err)
+}
+
+func TestMiner_GetMyNextSlot(t *testing.T) {
+	deputyCount := 3
+	dm := deputynode.NewManager(deputyCount, &testChain{})
+	dm.SaveSnapshot(0, testDeputies[:deputyCount])
+
+	var mineTimeout int64 = 2000
+	parentTime := uint32(time.Now().Unix())
+	chain := &testChain{currentBlock: &types.Block{Header: &types.Header{Height: 1, MinerAddress: testDeputies[1].MinerAddress, Time: parentTime}}}
+	miner := New(MineConfig{SleepTime: 1000, Timeout: mineTimeout}, chain, dm, nil)
+
+	// I am the first deputy
+	slot, err := miner.GetMyNextSlot()
+	assert.NoError(t, err)
+	assert.Equal(t, testDeputies[0].MinerAddress, slot.MinerAddress)
+	assert.Equal(t, int64(parentTime)*1000+mineTimeout, slot.WindowFrom)
+
+	// not deputy
+	others := make(types.DeputyNodes, 0, deputyCount)
+	for i, deputy := range testDeputies[1 : deputyCount+1] {
+		node := deputy.Copy()
+		node.Rank = uint32(i)
+		others = append(others, node)
+	}
+	dm = deputynode.NewManager(deputyCount, &testChain{})
+	dm.SaveSnapshot(0, others)
+	chain.currentBlock.Header.MinerAddress = testDeputies[2].MinerAddress
+	miner = New(MineConfig{SleepTime: 1000, Timeout: mineTimeout}, chain, dm, nil)
+	_, err = miner.GetMyNextSlot()
+	assert.Equal(t, deputynode.ErrNotDeputy, err)
+}
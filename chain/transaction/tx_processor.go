@@ -3,13 +3,13 @@ package transaction
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/account"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/vm"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/common/math"
@@ -27,7 +27,7 @@ const (
 	MaxIntroductionLength            = 1024
 	MaxMarshalCandidateProfileLength = 1200 // candidate 中profile marshal之后得到的byte数组的最大长度
 	StandardNodeIdLength             = 64
-	SignerWeightThreshold            = 100
+	SignerWeightThreshold            = types.SignerWeightThreshold
 	MaxSignersNumber                 = 100
 )
 
@@ -50,6 +50,7 @@ var (
 	ErrSetMulisig                = errors.New("from and to must be equal")
 	ErrAddressType               = errors.New("address type wrong")
 	ErrTempAddress               = errors.New("the issuer part in temp address is incorrect")
+	ErrTxNotInBlock              = errors.New("the transaction is not in the block")
 )
 
 type TxProcessor struct {
@@ -58,7 +59,13 @@ type TxProcessor struct {
 	am          *account.Manager
 	dm          *deputynode.Manager
 	db          protocol.ChainDB
-	cfg         *vm.Config // configuration of vm
+	cfg         *vm.Config     // configuration of vm
+	receipts    types.Receipts // receipts of the transactions in the last processed block
+	invalidErrs []error        // the reasons of invalid transactions in the last ApplyTxs call
+
+	// tracer records the vm steps of the transaction with hash traceTxHash
+	tracer      vm.Tracer
+	traceTxHash common.Hash
 
 	lock sync.Mutex
 }
@@ -89,6 +96,7 @@ func (p *TxProcessor) Process(header *types.Header, txs types.Transactions) (uin
 	)
 
 	p.am.Reset(header.ParentHash)
+	p.receipts = make(types.Receipts, 0, len(txs))
 
 	// Process genesis block. It's a develop error
 	if header.Height == 0 {
@@ -129,6 +137,8 @@ func (p *TxProcessor) ApplyTxs(header *types.Header, txs types.Transactions, tim
 	)
 
 	p.am.Reset(header.ParentHash)
+	p.receipts = make(types.Receipts, 0, len(txs))
+	p.invalidErrs = make([]error, 0)
 
 	now := time.Now() // 当前时间，用于计算箱子交易中执行子交易的限制时间
 	// limit the time to execute txs
@@ -154,10 +164,13 @@ txsLoop:
 		}
 		// Start executing the transaction
 		snap := p.am.Snapshot()
+		receiptsCount := len(p.receipts)
 
 		gas, err := p.applyTx(gp, header, tx, uint(len(selectedTxs)), common.Hash{}, restApplyTime)
 		if err != nil {
 			p.am.RevertToSnapshot(snap)
+			// drop the receipts of sub transactions in the failed box transaction
+			p.receipts = p.receipts[:receiptsCount]
 			if err == types.ErrGasLimitReached {
 				// block is full
 				log.Info("Not enough gas for further transactions", "gp", gp, "lastTxGasLimit", tx.GasLimit())
@@ -166,6 +179,7 @@ txsLoop:
 				// Strange error, discard the transaction and get the next in line.
 				log.Info("Skipped invalid transaction", "hash", tx.Hash(), "err", err)
 				invalidTxs = append(invalidTxs, tx)
+				p.invalidErrs = append(p.invalidErrs, err)
 			}
 			continue
 		}
@@ -184,6 +198,17 @@ txsLoop:
 	return selectedTxs, invalidTxs, gasUsed
 }
 
+// Receipts returns the receipts of transactions which are processed by the last Process or ApplyTxs call.
+// The receipts of sub transactions in box are also included, and they are in front of their box transaction's receipt
+func (p *TxProcessor) Receipts() types.Receipts {
+	return p.receipts
+}
+
+// InvalidTxErrs returns the reasons of invalid transactions which are dropped by the last ApplyTxs call. They are in the same order as the invalid transactions
+func (p *TxProcessor) InvalidTxErrs() []error {
+	return p.invalidErrs
+}
+
 // buyAndPayIntrinsicGas
 func (p *TxProcessor) buyAndPayIntrinsicGas(gp *types.GasPool, tx *types.Transaction, gasLimit uint64) (uint64, error) {
 	err := p.buyGas(gp, tx)
@@ -219,16 +244,8 @@ func (p *TxProcessor) checkSignersWeight(sender common.Address, tx *types.Transa
 			return ErrSignerAndFromUnequally
 		}
 	} else { // 多签账户
-		signersMap := accSigners.ToSignerMap()
-		// 计算签名者权重总和
-		var totalWeight int64 = 0
-		for _, addr := range signers {
-			if w, ok := signersMap[addr]; ok {
-				totalWeight = totalWeight + int64(w)
-			}
-		}
 		// 比较签名权重总和大小
-		if totalWeight < SignerWeightThreshold {
+		if accSigners.Weight(signers) < SignerWeightThreshold {
 			return ErrTotalWeight
 		}
 	}
@@ -340,7 +357,12 @@ func (p *TxProcessor) applyTx(gp *types.GasPool, header *types.Header, tx *types
 	if err != nil {
 		return 0, err
 	}
+	gasUsed, _, err := p.executeTx(gp, header, tx, txIndex, blockHash, restApplyTime)
+	return gasUsed, err
+}
 
+// executeTx executes the verified transaction and creates its receipt. It returns the gas used and the error from vm
+func (p *TxProcessor) executeTx(gp *types.GasPool, header *types.Header, tx *types.Transaction, txIndex uint, blockHash common.Hash, restApplyTime int64) (uint64, error, error) {
 	var (
 		senderAddr = tx.From()
 		sender     = p.am.GetAccount(senderAddr)
@@ -349,17 +371,20 @@ func (p *TxProcessor) applyTx(gp *types.GasPool, header *types.Header, tx *types
 		restGas              = tx.GasLimit()
 		vmErr, execErr       error
 		gasUsed              uint64
+		internalTxs          []*types.InternalTx
+		logsCount            = len(p.am.GetChangeLogs())
+		err                  error
 	)
 
 	restGas, err = p.buyAndPayIntrinsicGas(gp, tx, restGas)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	// 执行交易. 注：如果此交易为箱子交易，则返回的gasUsed为箱子中的子交易消耗gas与箱子交易本身消耗gas之和
-	restGas, gasUsed, vmErr, execErr = p.handleTx(tx, header, txIndex, blockHash, initialSenderBalance, restGas, gp, restApplyTime)
+	restGas, gasUsed, internalTxs, vmErr, execErr = p.handleTx(tx, header, txIndex, blockHash, initialSenderBalance, restGas, gp, restApplyTime)
 	if execErr != nil {
 		log.Errorf("Apply transaction failure. error:%s, transaction: %s.", execErr.Error(), tx.String())
-		return 0, execErr
+		return 0, nil, execErr
 	}
 
 	if vmErr != nil {
@@ -368,17 +393,47 @@ func (p *TxProcessor) applyTx(gp *types.GasPool, header *types.Header, tx *types
 		// sufficient balance to make the transfer happen. The first
 		// balance transfer may never fail.
 		if vmErr == vm.ErrInsufficientBalance {
-			return 0, vmErr
+			return 0, vmErr, vmErr
 		}
 	}
 	p.refundGas(gp, tx, restGas)
+	receipt := p.newReceipt(tx, vmErr, gasUsed, tx.GasLimit()-restGas, logsCount)
+	if len(internalTxs) > 0 {
+		receipt.InternalTxs = internalTxs
+	}
+	p.receipts = append(p.receipts, receipt)
 
-	return gasUsed, nil
+	return gasUsed, vmErr, nil
 }
 
-// handleTx 执行交易,返回消耗之后剩余的gas、evm中执行的error和交易执行不成功的error.
+// newReceipt creates the receipt of an applied transaction. selfGasUsed doesn't contain the gas used by sub transactions in box.
+// logsCount is the count of change logs before the transaction is applied, so that we can pick out the events of this transaction
+func (p *TxProcessor) newReceipt(tx *types.Transaction, vmErr error, gasUsed, selfGasUsed uint64, logsCount int) *types.Receipt {
+	// the gas of sub transactions has been counted in their own receipts
+	cumulativeGasUsed := selfGasUsed
+	if len(p.receipts) > 0 {
+		cumulativeGasUsed += p.receipts[len(p.receipts)-1].CumulativeGasUsed
+	}
+	receipt := types.NewReceipt(tx.Hash(), vmErr, gasUsed, cumulativeGasUsed)
+
+	for _, changeLog := range p.am.GetChangeLogs()[logsCount:] {
+		if changeLog.LogType != account.AddEventLog {
+			continue
+		}
+		// the events of sub transactions are not belong to box transaction. The index of event in block is set when account manager finalises
+		if event, ok := changeLog.NewVal.(*types.Event); ok && event.TxHash == tx.Hash() {
+			receipt.Events = append(receipt.Events, event)
+		}
+	}
+	if tx.Type() == params.CreateContractTx && vmErr == nil {
+		receipt.ContractAddress = crypto.CreateContractAddress(tx.From(), tx.Hash())
+	}
+	return receipt
+}
+
+// handleTx 执行交易,返回消耗之后剩余的gas、合约中的转账、evm中执行的error和交易执行不成功的error.
 // 注：initialSenderBalance参数代表的是sender执行交易之前的balance值，为投票交易中计算初始票数使用
-func (p *TxProcessor) handleTx(tx *types.Transaction, header *types.Header, txIndex uint, blockHash common.Hash, initialSenderBalance *big.Int, restGas uint64, gp *types.GasPool, restApplyTime int64) (gas, gasUsed uint64, vmErr, err error) {
+func (p *TxProcessor) handleTx(tx *types.Transaction, header *types.Header, txIndex uint, blockHash common.Hash, initialSenderBalance *big.Int, restGas uint64, gp *types.GasPool, restApplyTime int64) (gas, gasUsed uint64, internalTxs []*types.InternalTx, vmErr, err error) {
 	senderAddr := tx.From()
 	var (
 		recipientAddr common.Address
@@ -395,12 +450,14 @@ func (p *TxProcessor) handleTx(tx *types.Transaction, header *types.Header, txIn
 	switch tx.Type() {
 	case params.OrdinaryTx:
 		newContext := NewEVMContext(tx, header, txIndex, blockHash, p.blockLoader)
-		vmEnv := vm.NewEVM(newContext, p.am, *p.cfg)
+		vmEnv := vm.NewEVM(newContext, p.am, p.vmConfig(tx))
 		_, restGas, vmErr = vmEnv.Call(sender, recipientAddr, tx.Data(), restGas, tx.Amount())
+		internalTxs = vmEnv.InternalTxs()
 	case params.CreateContractTx:
 		newContext := NewEVMContext(tx, header, txIndex, blockHash, p.blockLoader)
-		vmEnv := vm.NewEVM(newContext, p.am, *p.cfg)
+		vmEnv := vm.NewEVM(newContext, p.am, p.vmConfig(tx))
 		_, recipientAddr, restGas, vmErr = vmEnv.Create(sender, tx.Data(), restGas, tx.Amount())
+		internalTxs = vmEnv.InternalTxs()
 	case params.VoteTx:
 		candidateVoteEnv := NewCandidateVoteEnv(p.am, p.dm)
 		err = candidateVoteEnv.CallVoteTx(senderAddr, recipientAddr, initialSenderBalance)
@@ -427,8 +484,9 @@ func (p *TxProcessor) handleTx(tx *types.Transaction, header *types.Header, txIn
 
 	case params.TransferAssetTx:
 		newContext := NewEVMContext(tx, header, txIndex, blockHash, p.blockLoader)
-		vmEnv := vm.NewEVM(newContext, p.am, *p.cfg)
+		vmEnv := vm.NewEVM(newContext, p.am, p.vmConfig(tx))
 		_, restGas, err, vmErr = vmEnv.TransferAssetTx(sender, recipientAddr, restGas, tx.Data(), p.db)
+		internalTxs = vmEnv.InternalTxs()
 	case params.ModifySignersTx:
 		multisigEnv := NewSetMultisigAccountEnv(p.am)
 		err = multisigEnv.ModifyMultisigTx(senderAddr, recipientAddr, tx.Data())
@@ -439,12 +497,12 @@ func (p *TxProcessor) handleTx(tx *types.Transaction, header *types.Header, txIn
 
 	default:
 		log.Errorf("The type of transaction is not defined. ErrType = %d\n", tx.Type())
-		return 0, 0, nil, types.ErrTxType
+		return 0, 0, nil, nil, types.ErrTxType
 	}
 	// 只有交易类型为BoxTx时，subTxsGasUsed才有值
 	gasUsed = gasLimit - restGas + subTxsGasUsed
 
-	return restGas, gasUsed, vmErr, err
+	return restGas, gasUsed, internalTxs, vmErr, err
 }
 
 func (p *TxProcessor) buyGas(gp *types.GasPool, tx *types.Transaction) error {
@@ -597,7 +655,9 @@ func (p *TxProcessor) ReadContract(accM *account.ReadOnlyManager, header *types.
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	accM.Reset(header.Hash())
+	if err := accM.Reset(header.Hash()); err != nil {
+		return nil, err
+	}
 
 	// A random address is found as our caller address.
 	// todo Consider let users pass in their own address
@@ -630,6 +690,139 @@ func (p *TxProcessor) ReadContract(accM *account.ReadOnlyManager, header *types.
 	return ret, err
 }
 
+// TraceTx re-executes the transaction in block, and the vm steps of it are recorded by tracer. It returns the receipt of the traced transaction.
+// The transactions in front of it are executed first to restore the state. The chain state is not changed
+func (p *TxProcessor) TraceTx(block *types.Block, txHash common.Hash, tracer vm.Tracer) (*types.Receipt, error) {
+	am, err := account.NewHistoryManager(block.ParentHash(), p.db)
+	if err != nil {
+		return nil, err
+	}
+	executor := NewTxProcessor(p.cfg.RewardManager, p.ChainID, p.blockLoader, am, p.db, p.dm)
+	executor.tracer = tracer
+	executor.traceTxHash = txHash
+	executor.receipts = make(types.Receipts, 0, len(block.Txs))
+
+	gp := new(types.GasPool).AddGas(block.GasLimit())
+	for i, tx := range block.Txs {
+		receiptsCount := len(executor.receipts)
+		// clone the tx because the data of box transaction will be changed after executed
+		if _, _, err := executor.executeTx(gp, block.Header, tx.Clone(), uint(i), block.Hash(), math.MaxInt64); err != nil {
+			return nil, err
+		}
+		// the traced transaction may be a sub transaction in box
+		for _, receipt := range executor.receipts[receiptsCount:] {
+			if receipt.TxHash == txHash {
+				return receipt, nil
+			}
+		}
+	}
+	return nil, ErrTxNotInBlock
+}
+
+// TraceCall executes a message call on the state of the block, and the vm steps are recorded by tracer. It creates a contract if to is nil
+func (p *TxProcessor) TraceCall(accM *account.ReadOnlyManager, header *types.Header, from common.Address, to *common.Address, data []byte, value *big.Int, gasLimit uint64, tracer vm.Tracer) (ret []byte, gasUsed uint64, vmErr error, err error) {
+	// rebuilding the history state may take a long time, so do it before locking the processor
+	if err = accM.Reset(header.Hash()); err != nil {
+		return nil, 0, nil, err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	cfg := *p.cfg
+	cfg.Debug = true
+	cfg.Tracer = tracer
+	var tx *types.Transaction
+	expiration := uint64(time.Now().Unix()) + uint64(params.TransactionExpiration)
+	if to == nil {
+		tx = types.NoReceiverTransaction(from, value, gasLimit, big.NewInt(defaultGasPrice), data, params.CreateContractTx, p.ChainID, expiration, "", "")
+	} else {
+		tx = types.NewTransaction(from, *to, value, gasLimit, big.NewInt(defaultGasPrice), data, params.OrdinaryTx, p.ChainID, expiration, "", "")
+	}
+	var (
+		sender  = accM.GetAccount(from)
+		vmEnv   = getEVM(tx, header, 0, common.Hash{}, p.blockLoader, cfg, accM)
+		restGas uint64
+	)
+	if to == nil {
+		ret, _, restGas, vmErr = vmEnv.Create(sender, data, gasLimit, value)
+	} else {
+		ret, restGas, vmErr = vmEnv.Call(sender, *to, data, gasLimit, value)
+	}
+	return ret, gasLimit - restGas, vmErr, nil
+}
+
+// vmConfig returns the vm configuration for the transaction. Only the traced transaction uses tracer
+func (p *TxProcessor) vmConfig(tx *types.Transaction) vm.Config {
+	if p.tracer != nil && tx.Hash() == p.traceTxHash {
+		cfg := *p.cfg
+		cfg.Debug = true
+		cfg.Tracer = p.tracer
+		return cfg
+	}
+	return *p.cfg
+}
+
+// EstimateGas executes the transaction on the state of parent block with different gas limits, and finds out the minimal gas limit which makes it success.
+// The signatures of transaction are not verified, and the chain state is not changed. It returns the vm error if the transaction always fails
+func (p *TxProcessor) EstimateGas(parent *types.Block, tx *types.Transaction) (uint64, error) {
+	if err := p.VerifyAssetTx(tx); err != nil {
+		return 0, err
+	}
+	intrinsicGas, err := IntrinsicGas(tx.Type(), tx.Data(), tx.Message())
+	if err != nil {
+		return 0, err
+	}
+
+	// The asset, vote and box environments need a writable account manager. So we use a temporary one which is never saved
+	am := account.NewManager(parent.Hash(), p.db)
+	estimator := NewTxProcessor(p.cfg.RewardManager, p.ChainID, p.blockLoader, am, p.db, p.dm)
+	header := &types.Header{
+		ParentHash:   parent.Hash(),
+		MinerAddress: parent.MinerAddress(),
+		Height:       parent.Height() + 1,
+		GasLimit:     parent.GasLimit(),
+		Time:         uint32(time.Now().Unix()),
+	}
+
+	// the gas limit can't be larger than block gas limit, or the balance of gas payer
+	hi := header.GasLimit
+	if tx.GasPrice().Sign() > 0 {
+		allowance := new(big.Int).Div(am.GetAccount(tx.GasPayer()).GetBalance(), tx.GasPrice())
+		if allowance.IsUint64() && allowance.Uint64() < hi {
+			hi = allowance.Uint64()
+		}
+	}
+	if hi < intrinsicGas {
+		return 0, ErrInsufficientBalanceForGas
+	}
+
+	tryGasLimit := func(gasLimit uint64) error {
+		am.Reset(parent.Hash())
+		estimator.receipts = make(types.Receipts, 0)
+		gp := new(types.GasPool).AddGas(header.GasLimit)
+		_, vmErr, err := estimator.executeTx(gp, header, types.GasPayerSignatureTx(tx.Clone(), tx.GasPrice(), gasLimit), 0, common.Hash{}, math.MaxInt64)
+		if err != nil {
+			return err
+		}
+		return vmErr
+	}
+	// make sure the transaction could be success
+	if err := tryGasLimit(hi); err != nil {
+		return 0, err
+	}
+	// binary search the minimal gas limit. lo always fails and hi always succeeds
+	lo := intrinsicGas - 1
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if tryGasLimit(mid) == nil {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
 // getEVM
 func getEVM(tx *types.Transaction, header *types.Header, txIndex uint, blockHash common.Hash, chain ParentBlockLoader, cfg vm.Config, accM vm.AccountManager) *vm.EVM {
 	evmContext := NewEVMContext(tx, header, txIndex, blockHash, chain)
@@ -8,6 +8,7 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/vm"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
@@ -93,6 +94,229 @@ func TestTxProcessor_Process_applyTxs(t *testing.T) {
 
 }
 
+// TestTxProcessor_Receipts 测试 process 和 applyTxs 产生的交易回执
+func TestTxProcessor_Receipts(t *testing.T) {
+	ClearData()
+	db, genesisHash := newCoverGenesisDB()
+	defer db.Close()
+	am := account.NewManager(genesisHash, db)
+	dm := deputynode.NewManager(5, db)
+	p := NewTxProcessor(config.RewardManager, config.ChainID, newTestChain(db), am, db, dm)
+
+	txs := make(types.Transactions, 0)
+	for i := 0; i < 3; i++ {
+		tx := makeTx(godPrivate, godAddr, common.HexToAddress("0x9920"+strconv.Itoa(i)), nil, params.OrdinaryTx, big.NewInt(50000))
+		txs = append(txs, tx)
+	}
+	parentBlock, err := db.LoadLatestBlock()
+	assert.NoError(t, err)
+	header := &types.Header{
+		ParentHash:   parentBlock.Hash(),
+		MinerAddress: parentBlock.MinerAddress(),
+		Height:       parentBlock.Height() + 1,
+		GasLimit:     parentBlock.GasLimit(),
+	}
+	// 打包交易
+	selectedTxs, _, gasUsed := p.ApplyTxs(header, txs, 1000)
+	assert.Equal(t, 3, len(selectedTxs))
+	applyTxsReceipts := p.Receipts()
+	assert.Equal(t, 3, len(applyTxsReceipts))
+	var cumulativeGasUsed uint64
+	for i, receipt := range applyTxsReceipts {
+		cumulativeGasUsed += txs[i].GasUsed()
+		assert.Equal(t, txs[i].Hash(), receipt.TxHash)
+		assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+		assert.Equal(t, "", receipt.VmErr)
+		assert.Equal(t, txs[i].GasUsed(), receipt.GasUsed)
+		assert.Equal(t, cumulativeGasUsed, receipt.CumulativeGasUsed)
+		assert.Equal(t, common.Address{}, receipt.ContractAddress)
+	}
+	assert.Equal(t, gasUsed, cumulativeGasUsed)
+
+	// 验证区块时产生的回执与打包时一致
+	_, err = p.Process(header, selectedTxs)
+	assert.NoError(t, err)
+	assert.Equal(t, applyTxsReceipts, p.Receipts())
+}
+
+func TestTxProcessor_EstimateGas(t *testing.T) {
+	ClearData()
+	db, genesisHash := newCoverGenesisDB()
+	defer db.Close()
+	am := account.NewManager(genesisHash, db)
+	dm := deputynode.NewManager(5, db)
+	p := NewTxProcessor(config.RewardManager, config.ChainID, newTestChain(db), am, db, dm)
+	parentBlock, err := db.LoadLatestBlock()
+	assert.NoError(t, err)
+	expiration := uint64(time.Now().Unix() + 30*60)
+
+	// ordinary tx without signature
+	tx := types.NewTransaction(godAddr, common.HexToAddress("0x99201"), big.NewInt(100), 0, common.Big1, nil, params.OrdinaryTx, chainID, expiration, "", "")
+	gas, err := p.EstimateGas(parentBlock, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, params.OrdinaryTxGas, gas)
+
+	// message costs gas
+	tx = types.NewTransaction(godAddr, common.HexToAddress("0x99201"), big.NewInt(100), 0, common.Big1, nil, params.OrdinaryTx, chainID, expiration, "", "hello")
+	gas, err = p.EstimateGas(parentBlock, tx)
+	assert.NoError(t, err)
+	intrinsicGas, _ := IntrinsicGas(params.OrdinaryTx, nil, "hello")
+	assert.Equal(t, intrinsicGas, gas)
+
+	// create contract
+	filePath, _ := filepath.Abs("../transaction/contract_code.txt")
+	code, err := ioutil.ReadFile(filePath)
+	assert.NoError(t, err)
+	data := common.FromHex(string(code))
+	tx = types.NewContractCreation(godAddr, nil, 0, common.Big1, data, params.CreateContractTx, chainID, expiration, "", "")
+	gas, err = p.EstimateGas(parentBlock, tx)
+	assert.NoError(t, err)
+	intrinsicGas, _ = IntrinsicGas(params.CreateContractTx, data, "")
+	assert.True(t, gas > intrinsicGas)
+	header := &types.Header{
+		ParentHash:   parentBlock.Hash(),
+		MinerAddress: parentBlock.MinerAddress(),
+		Height:       parentBlock.Height() + 1,
+		GasLimit:     parentBlock.GasLimit(),
+	}
+	createTx := signTransaction(types.NewContractCreation(godAddr, nil, gas, common.Big1, data, params.CreateContractTx, chainID, expiration, "", ""), godPrivate)
+	selectedTxs, _, _ := p.ApplyTxs(header, types.Transactions{createTx}, 1000)
+	assert.Equal(t, 1, len(selectedTxs))
+	assert.Equal(t, types.ReceiptStatusSuccessful, p.Receipts()[0].Status)
+	createTx = signTransaction(types.NewContractCreation(godAddr, nil, gas-1, common.Big1, data, params.CreateContractTx, chainID, expiration, "", ""), godPrivate)
+	p.ApplyTxs(header, types.Transactions{createTx}, 1000)
+	assert.Equal(t, types.ReceiptStatusFailed, p.Receipts()[0].Status)
+
+	// always fail
+	tx = types.NewTransaction(godAddr, common.HexToAddress("0x99201"), new(big.Int).Add(account.NewManager(parentBlock.Hash(), db).GetAccount(godAddr).GetBalance(), common.Big1), 0, common.Big0, nil, params.OrdinaryTx, chainID, expiration, "", "")
+	_, err = p.EstimateGas(parentBlock, tx)
+	assert.Equal(t, vm.ErrInsufficientBalance, err)
+	tx = types.NewTransaction(common.HexToAddress("0x99202"), godAddr, big.NewInt(0), 0, common.Big1, nil, params.OrdinaryTx, chainID, expiration, "", "")
+	_, err = p.EstimateGas(parentBlock, tx)
+	assert.Equal(t, ErrInsufficientBalanceForGas, err)
+}
+
+func TestTxProcessor_TraceTx(t *testing.T) {
+	ClearData()
+	db, genesisHash := newCoverGenesisDB()
+	defer db.Close()
+	am := account.NewManager(genesisHash, db)
+	dm := deputynode.NewManager(5, db)
+	p := NewTxProcessor(config.RewardManager, config.ChainID, newTestChain(db), am, db, dm)
+
+	filePath, _ := filepath.Abs("../transaction/contract_code.txt")
+	code, err := ioutil.ReadFile(filePath)
+	assert.NoError(t, err)
+	expiration := uint64(time.Now().Unix() + 30*60)
+	transferTx := makeTx(godPrivate, godAddr, common.HexToAddress("0x99201"), nil, params.OrdinaryTx, big.NewInt(100))
+	createTx := signTransaction(types.NewContractCreation(godAddr, nil, uint64(5000000), common.Big1, common.FromHex(string(code)), params.CreateContractTx, chainID, expiration, "", ""), godPrivate)
+	block := newBlockForTest(1, types.Transactions{transferTx, createTx}, am, dm, db, true)
+	assert.Equal(t, 2, len(block.Txs))
+
+	// trace the second transaction in block
+	tracer := vm.NewStructLogger(nil)
+	receipt, err := p.TraceTx(block, createTx.Hash(), tracer)
+	assert.NoError(t, err)
+	assert.Equal(t, createTx.Hash(), receipt.TxHash)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	assert.Equal(t, block.Txs[1].GasUsed(), receipt.GasUsed)
+	assert.NotEmpty(t, tracer.StructLogs())
+	assert.NotEmpty(t, tracer.Output())
+
+	// the contract creates another contract in its constructor
+	callTracer := vm.NewCallTracer()
+	_, err = p.TraceTx(block, createTx.Hash(), callTracer)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE", callTracer.Result().Type)
+	assert.Equal(t, crypto.CreateContractAddress(godAddr, createTx.Hash()), callTracer.Result().To)
+	assert.Len(t, callTracer.Result().Calls, 1)
+	assert.Equal(t, "CREATE", callTracer.Result().Calls[0].Type)
+
+	// no vm step in ordinary transfer
+	tracer = vm.NewStructLogger(nil)
+	_, err = p.TraceTx(block, transferTx.Hash(), tracer)
+	assert.NoError(t, err)
+	assert.Empty(t, tracer.StructLogs())
+
+	// not exist
+	_, err = p.TraceTx(block, common.HexToHash("0x1234"), vm.NewStructLogger(nil))
+	assert.Equal(t, ErrTxNotInBlock, err)
+
+	// trace call name() of the contract
+	contractAddr := crypto.CreateContractAddress(godAddr, createTx.Hash())
+	tracer = vm.NewStructLogger(nil)
+	ret, gasUsed, vmErr, err := p.TraceCall(account.NewReadOnlyManager(db, false), block.Header, godAddr, &contractAddr, getContractFunctionCode("name()"), big.NewInt(0), 1000000, tracer)
+	assert.NoError(t, err)
+	assert.NoError(t, vmErr)
+	assert.Equal(t, "LemoCoin", regexMatchLetter(string(ret)))
+	assert.True(t, gasUsed > 0)
+	assert.NotEmpty(t, tracer.StructLogs())
+
+	// the context of traced contract creation is same as a real transaction. The init code returns GASPRICE or ORIGIN
+	ret, _, vmErr, err = p.TraceCall(account.NewReadOnlyManager(db, false), block.Header, godAddr, nil, common.FromHex("0x3a60005260206000f3"), big.NewInt(0), 1000000, vm.NewStructLogger(nil))
+	assert.NoError(t, err)
+	assert.NoError(t, vmErr)
+	assert.Equal(t, big.NewInt(defaultGasPrice), new(big.Int).SetBytes(ret))
+	ret, _, vmErr, err = p.TraceCall(account.NewReadOnlyManager(db, false), block.Header, godAddr, nil, common.FromHex("0x3260005260206000f3"), big.NewInt(0), 1000000, vm.NewStructLogger(nil))
+	assert.NoError(t, err)
+	assert.NoError(t, vmErr)
+	assert.Equal(t, godAddr, common.BytesToAddress(ret))
+}
+
+func TestTxProcessor_ReadContract_History(t *testing.T) {
+	ClearData()
+	db, genesisHash := newCoverGenesisDB()
+	defer db.Close()
+	am := account.NewManager(genesisHash, db)
+	dm := deputynode.NewManager(5, db)
+	p := NewTxProcessor(config.RewardManager, config.ChainID, newTestChain(db), am, db, dm)
+
+	filePath, _ := filepath.Abs("../transaction/contract_code.txt")
+	code, err := ioutil.ReadFile(filePath)
+	assert.NoError(t, err)
+	expiration := uint64(time.Now().Unix() + 30*60)
+	createTx := signTransaction(types.NewContractCreation(godAddr, nil, uint64(5000000), common.Big1, common.FromHex(string(code)), params.CreateContractTx, chainID, expiration, "", ""), godPrivate)
+	block1 := newBlockForTest(1, types.Transactions{createTx}, am, dm, db, true)
+	receiver := common.HexToAddress("0x99201")
+	transferTx := makeTx(godPrivate, godAddr, receiver, nil, params.OrdinaryTx, big.NewInt(100))
+	block2 := newBlockForTest(2, types.Transactions{transferTx}, am, dm, db, true)
+	contractAddr := crypto.CreateContractAddress(godAddr, createTx.Hash())
+
+	// the manager without history can't read block1
+	_, err = readContraction(p, db, block1.Header, contractAddr, getContractFunctionCode("name()"))
+	assert.Equal(t, account.ErrHistoryUnavailable, err)
+
+	// the contract exists in old stable block
+	ret, err := p.ReadContract(account.NewHistoryReadOnlyManager(db), block1.Header, contractAddr, getContractFunctionCode("name()"), 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "LemoCoin", regexMatchLetter(string(ret)))
+	// the contract doesn't exist in genesis block
+	genesis, err := db.GetBlockByHeight(0)
+	assert.NoError(t, err)
+	ret, err = p.ReadContract(account.NewHistoryReadOnlyManager(db), genesis.Header, contractAddr, getContractFunctionCode("name()"), 5*time.Second)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	// account states at different heights
+	accM := account.NewHistoryReadOnlyManager(db)
+	assert.NoError(t, accM.Reset(block1.Hash()))
+	assert.Equal(t, big.NewInt(0), accM.GetAccount(receiver).GetBalance())
+	contractCode, err := accM.GetAccount(contractAddr).GetCode()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, contractCode)
+	assert.NoError(t, accM.Reset(block2.Hash()))
+	assert.Equal(t, big.NewInt(100), accM.GetAccount(receiver).GetBalance())
+	assert.NoError(t, accM.Reset(genesis.Hash()))
+	contractCode, err = accM.GetAccount(contractAddr).GetCode()
+	assert.NoError(t, err)
+	assert.Empty(t, contractCode)
+
+	// not exist
+	assert.Equal(t, account.ErrBlockNotExist, accM.Reset(common.HexToHash("0x1234")))
+	_, err = readContraction(p, db, &types.Header{Height: 100}, contractAddr, getContractFunctionCode("name()"))
+	assert.Equal(t, account.ErrBlockNotExist, err)
+}
+
 // Test_ApplyTxs_TimeoutTime 测试执行交易超时情况
 func Test_ApplyTxs_TimeoutTime(t *testing.T) {
 	ClearData()
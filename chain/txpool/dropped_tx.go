@@ -0,0 +1,63 @@
+package txpool
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+)
+
+const (
+	TxStatusUnknown = "unknown" // 交易池中没有该交易的记录
+	TxStatusPending = "pending" // 在交易池中等待打包
+	TxStatusPacked  = "packed"  // 已被打包进未稳定的区块
+	TxStatusExpired = "expired" // 超时未被打包
+	TxStatusDropped = "dropped" // 执行失败被交易池删除
+)
+
+// DroppedTxKeepTime is the seconds to keep the drop reasons of transactions. It can be set in config file
+var DroppedTxKeepTime = uint64(params.TransactionExpiration)
+
+// TxStatus is the status of a transaction in tx pool
+type TxStatus struct {
+	Status string `json:"status"`
+	// the unstable blocks which have packed the transaction. They may be in different forks
+	BlockHashes []common.Hash `json:"blockHashes,omitempty"`
+	// the reason why the transaction is dropped
+	Reason string `json:"reason,omitempty"`
+}
+
+// DroppedTx is the record of a transaction which is removed from tx pool without being packed
+type DroppedTx struct {
+	Status string
+	Reason string
+	// the time when the transaction is dropped, UTC seconds
+	Time uint64
+}
+
+/* 最近被交易池删除的交易和删除原因 */
+type DroppedTxs struct {
+	records map[common.Hash]*DroppedTx
+}
+
+func NewDroppedTxs() *DroppedTxs {
+	return &DroppedTxs{records: make(map[common.Hash]*DroppedTx)}
+}
+
+// Add records the dropped transaction. The status is TxStatusExpired or TxStatusDropped
+func (d *DroppedTxs) Add(hash common.Hash, status string, reason string, time uint64) {
+	d.records[hash] = &DroppedTx{Status: status, Reason: reason, Time: time}
+}
+
+// Get returns the drop record of the transaction
+func (d *DroppedTxs) Get(hash common.Hash) (*DroppedTx, bool) {
+	record, ok := d.records[hash]
+	return record, ok
+}
+
+// Prune removes the records which are kept longer than DroppedTxKeepTime
+func (d *DroppedTxs) Prune(time uint64) {
+	for hash, record := range d.records {
+		if record.Time+DroppedTxKeepTime < time {
+			delete(d.records, hash)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package txpool
+
+import (
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common/log"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
+	"io"
+	"os"
+)
+
+var ErrNoActiveJournal = errors.New("no active journal")
+
+// devNull is a WriteCloser that just discards anything written into it
+type devNull struct{}
+
+func (*devNull) Write(p []byte) (n int, err error) { return len(p), nil }
+func (*devNull) Close() error                      { return nil }
+
+/* 交易池的磁盘日志，节点重启后用于恢复交易池中的交易 */
+type txJournal struct {
+	path   string
+	writer io.WriteCloser
+}
+
+func newTxJournal(path string) *txJournal {
+	return &txJournal{path: path}
+}
+
+// load 读取日志中的所有交易并逐笔调用add，返回读取的交易数量和被拒收的数量
+func (journal *txJournal) load(add func(tx *types.Transaction) error) (total int, dropped int, err error) {
+	if _, err := os.Stat(journal.path); os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	input, err := os.Open(journal.path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer input.Close()
+
+	// 读取时不写入日志
+	journal.writer = new(devNull)
+	defer func() { journal.writer = nil }()
+
+	stream := rlp.NewStream(input, 0)
+	for {
+		tx := new(types.Transaction)
+		if err = stream.Decode(tx); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			break
+		}
+		total++
+		if addErr := add(tx); addErr != nil {
+			log.Debugf("Failed to add journaled transaction. hash: %s, err: %v", tx.Hash().Hex(), addErr)
+			dropped++
+		}
+	}
+	return total, dropped, err
+}
+
+// insert 把交易追加到日志中
+func (journal *txJournal) insert(tx *types.Transaction) error {
+	if journal.writer == nil {
+		return ErrNoActiveJournal
+	}
+	return rlp.Encode(journal.writer, tx)
+}
+
+// rotate 用交易池中当前的交易重新生成日志，丢弃已经被打包或删除的交易
+func (journal *txJournal) rotate(txs []*types.Transaction) error {
+	if journal.writer != nil {
+		if err := journal.writer.Close(); err != nil {
+			return err
+		}
+		journal.writer = nil
+	}
+
+	replacement, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err = rlp.Encode(replacement, tx); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	replacement.Close()
+
+	if err = os.Rename(journal.path+".new", journal.path); err != nil {
+		return err
+	}
+	sink, err := os.OpenFile(journal.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	journal.writer = sink
+	log.Infof("Regenerated tx pool journal. count: %d", len(txs))
+	return nil
+}
+
+// close 关闭日志文件
+func (journal *txJournal) close() error {
+	var err error
+	if journal.writer != nil {
+		err = journal.writer.Close()
+		journal.writer = nil
+	}
+	return err
+}
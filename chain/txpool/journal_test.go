@@ -0,0 +1,61 @@
+package txpool
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func txHashes(txs ...*types.Transaction) []common.Hash {
+	hashes := make([]common.Hash, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes
+}
+
+func TestTxPool_Journal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool-journal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "transactions.rlp")
+
+	// no journal file
+	pool := NewTxPool()
+	assert.NoError(t, pool.LoadJournal(path, chainID))
+	assert.Equal(t, 0, pool.PendingTxs.Len())
+
+	tx1 := makeTxRandom(common.HexToAddress("0x01"))
+	tx2 := makeTxRandom(common.HexToAddress("0x02"))
+	tx3 := makeTxRandom(common.HexToAddress("0x03"))
+	assert.True(t, pool.RecvTx(tx1))
+	assert.True(t, pool.RecvTx(tx2))
+	assert.True(t, pool.RecvTx(tx3))
+	pool.Stop()
+
+	// transactions are recovered after restart
+	pool = NewTxPool()
+	assert.NoError(t, pool.LoadJournal(path, chainID))
+	assert.Equal(t, txHashes(tx1, tx2, tx3), txHashes(pool.PendingTxs.Lives(uint32(time.Now().Unix()))...))
+
+	// packed transaction is removed from journal after rotation
+	pool.RecvBlock(&types.Block{Header: &types.Header{Height: 1}, Txs: types.Transactions{tx2}})
+	assert.NoError(t, pool.RotateJournal())
+	pool.Stop()
+	pool = NewTxPool()
+	assert.NoError(t, pool.LoadJournal(path, chainID))
+	assert.Equal(t, txHashes(tx1, tx3), txHashes(pool.PendingTxs.Lives(uint32(time.Now().Unix()))...))
+	pool.Stop()
+
+	// transactions which can't pass verification are dropped
+	pool = NewTxPool()
+	assert.NoError(t, pool.LoadJournal(path, chainID+1))
+	assert.Equal(t, 0, pool.PendingTxs.Len())
+	pool.Stop()
+	assert.Equal(t, ErrNoActiveJournal, pool.RotateJournal())
+}
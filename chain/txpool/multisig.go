@@ -0,0 +1,225 @@
+package txpool
+
+import (
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMultisigLimit        = 1000 // 默认最多保存的待签名多签交易数量
+	DefaultMultisigAccountLimit = 16   // 默认每个多签账户最多保存的待签名交易数量
+)
+
+var (
+	ErrNotMultisigAccount  = errors.New("the sender is not a multisig account")
+	ErrMultisigGasPayer    = errors.New("the multisig transaction whose gas is paid by another account is not supported")
+	ErrMultisigNotSigned   = errors.New("the multisig transaction must be signed by one of the signers")
+	ErrMultisigNotFound    = errors.New("the pending multisig transaction is not found")
+	ErrMultisigPoolFull    = errors.New("too many pending multisig transactions")
+	ErrMultisigAccountFull = errors.New("the account has too many pending multisig transactions")
+	ErrNotSigner           = errors.New("the signature is not from the signers of account")
+	ErrSignerIsExist       = errors.New("the signer has already signed the transaction")
+)
+
+// SignersLoader returns the signers of multisig account
+type SignersLoader func(address common.Address) types.Signers
+
+// MultisigTx is the transaction which is waiting for the signatures of multisig account
+type MultisigTx struct {
+	// 签名hash，添加签名后不变
+	Hash    common.Hash        `json:"hash"`
+	Tx      *types.Transaction `json:"tx"`
+	Signers []common.Address   `json:"signers"`
+	// 已签名的签名者的权重总和
+	Weight hexutil.Uint64 `json:"weight"`
+	// 权重达到阈值并提交到交易池后的交易hash
+	TxHash *common.Hash `json:"txHash,omitempty"`
+}
+
+func (m *MultisigTx) copy() *MultisigTx {
+	cpy := *m
+	cpy.Signers = make([]common.Address, len(m.Signers))
+	copy(cpy.Signers, m.Signers)
+	return &cpy
+}
+
+/* 多签账户的待签名交易，签名权重达到阈值后自动提交到交易池 */
+type MultisigPool struct {
+	loadSigners SignersLoader
+	submit      func(tx *types.Transaction) error
+
+	/* 最多保存的待签名交易数量，以及每个多签账户最多保存的待签名交易数量 */
+	Limit        int
+	AccountLimit int
+
+	pending map[common.Hash]*MultisigTx
+	mu      sync.Mutex
+}
+
+func NewMultisigPool(loadSigners SignersLoader, submit func(tx *types.Transaction) error) *MultisigPool {
+	return &MultisigPool{
+		loadSigners:  loadSigners,
+		submit:       submit,
+		Limit:        DefaultMultisigLimit,
+		AccountLimit: DefaultMultisigAccountLimit,
+		pending:      make(map[common.Hash]*MultisigTx),
+	}
+}
+
+// Propose saves the transaction of multisig account and waits for the signatures from other signers. The signatures in transaction are added too
+func (p *MultisigPool) Propose(tx *types.Transaction) (*MultisigTx, error) {
+	if tx.GasPayer() != tx.From() {
+		return nil, ErrMultisigGasPayer
+	}
+	if len(tx.Sigs()) == 0 {
+		return nil, ErrMultisigNotSigned
+	}
+	accSigners := p.loadSigners(tx.From())
+	if len(accSigners) == 0 {
+		return nil, ErrNotMultisigAccount
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune(uint64(time.Now().Unix()))
+
+	hash := types.MakeSigner().Hash(tx)
+	// 先在临时记录上验证所有签名，避免无效的提案淘汰其它待签名交易
+	exist, ok := p.pending[hash]
+	record := &MultisigTx{Hash: hash, Tx: tx.WithSigs(nil), Signers: []common.Address{}}
+	if ok {
+		record = exist.copy()
+	}
+	// 逐个验证交易中的签名，重复的签名者会被忽略
+	for _, sig := range tx.Sigs() {
+		if err := addSignature(record, accSigners, sig); err != nil && err != ErrSignerIsExist {
+			return nil, err
+		}
+	}
+	if !ok {
+		if err := p.makeRoom(tx.From()); err != nil {
+			return nil, err
+		}
+	}
+	p.pending[hash] = record
+	// the record is kept if submitting is failed, so that it can be checked by Get
+	err := p.trySubmit(record)
+	return record.copy(), err
+}
+
+// AddSignature adds the signature to the pending multisig transaction. The transaction is submitted to tx pool once the weight of signers reaches the threshold
+func (p *MultisigPool) AddSignature(hash common.Hash, sig []byte) (*MultisigTx, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune(uint64(time.Now().Unix()))
+
+	record, ok := p.pending[hash]
+	if !ok {
+		return nil, ErrMultisigNotFound
+	}
+	if err := addSignature(record, p.loadSigners(record.Tx.From()), sig); err != nil {
+		return nil, err
+	}
+	// the record is kept if submitting is failed, so that it can be checked by Get
+	err := p.trySubmit(record)
+	return record.copy(), err
+}
+
+// Get returns the pending multisig transactions of the account, sorted by expiration time
+func (p *MultisigPool) Get(address common.Address) []*MultisigTx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune(uint64(time.Now().Unix()))
+
+	result := make([]*MultisigTx, 0)
+	for _, record := range p.pending {
+		if record.Tx.From() == address {
+			result = append(result, record.copy())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Tx.Expiration() != result[j].Tx.Expiration() {
+			return result[i].Tx.Expiration() < result[j].Tx.Expiration()
+		}
+		return result[i].Hash.Hex() < result[j].Hash.Hex()
+	})
+	return result
+}
+
+// makeRoom 检查多签账户和全局的待签名交易数量。全局数量达到上限时淘汰最早过期的交易，账户数量达到上限时拒绝新的交易
+func (p *MultisigPool) makeRoom(from common.Address) error {
+	count := 0
+	var earliest *MultisigTx
+	for _, record := range p.pending {
+		if record.Tx.From() == from {
+			count++
+		}
+		if earliest == nil || record.Tx.Expiration() < earliest.Tx.Expiration() {
+			earliest = record
+		}
+	}
+	if count >= p.AccountLimit {
+		return ErrMultisigAccountFull
+	}
+	if len(p.pending) >= p.Limit {
+		if earliest == nil {
+			return ErrMultisigPoolFull
+		}
+		log.Infof("Multisig pool is full. Evict transaction %s", earliest.Hash.Hex())
+		delete(p.pending, earliest.Hash)
+	}
+	return nil
+}
+
+// addSignature 验证签名者属于多签账户，并重新计算签名权重
+func addSignature(record *MultisigTx, accSigners types.Signers, sig []byte) error {
+	tx := record.Tx.WithSigs(append(record.Tx.Sigs(), sig))
+	signers, err := types.MakeSigner().GetSigners(tx)
+	if err != nil {
+		return err
+	}
+	signer := signers[len(signers)-1]
+	if _, ok := accSigners.ToSignerMap()[signer]; !ok {
+		log.Warnf("The signer of multisig transaction is not in the signers of account. signer: %s. from: %s", signer.String(), tx.From().String())
+		return ErrNotSigner
+	}
+	for _, exist := range record.Signers {
+		if exist == signer {
+			return ErrSignerIsExist
+		}
+	}
+	record.Tx = tx
+	record.Signers = append(record.Signers, signer)
+	record.Weight = hexutil.Uint64(accSigners.Weight(record.Signers))
+	return nil
+}
+
+// trySubmit 签名权重达到阈值后提交到交易池，并删除待签名记录
+func (p *MultisigPool) trySubmit(record *MultisigTx) error {
+	if record.TxHash != nil || record.Weight < types.SignerWeightThreshold {
+		return nil
+	}
+	if err := p.submit(record.Tx); err != nil {
+		log.Errorf("Submit multisig transaction error: %s", err)
+		return err
+	}
+	txHash := record.Tx.Hash()
+	record.TxHash = &txHash
+	delete(p.pending, record.Hash)
+	return nil
+}
+
+// prune 删除过期的待签名交易
+func (p *MultisigPool) prune(time uint64) {
+	for hash, record := range p.pending {
+		if record.Tx.Expiration() < time {
+			delete(p.pending, hash)
+		}
+	}
+}
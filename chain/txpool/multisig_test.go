@@ -0,0 +1,172 @@
+package txpool
+
+import (
+	"crypto/ecdsa"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func signSig(t *testing.T, tx *types.Transaction, private *ecdsa.PrivateKey) []byte {
+	hash := testSigner.Hash(tx)
+	sig, err := crypto.Sign(hash[:], private)
+	assert.NoError(t, err)
+	return sig
+}
+
+func TestMultisigPool(t *testing.T) {
+	multisigAddr := common.HexToAddress("0x1234")
+	normalAddr := common.HexToAddress("0x5678")
+	private1, _ := crypto.GenerateKey()
+	private2, _ := crypto.GenerateKey()
+	private3, _ := crypto.GenerateKey()
+	outsider, _ := crypto.GenerateKey()
+	signer1 := crypto.PubkeyToAddress(private1.PublicKey)
+	signer2 := crypto.PubkeyToAddress(private2.PublicKey)
+	signer3 := crypto.PubkeyToAddress(private3.PublicKey)
+	loadSigners := func(address common.Address) types.Signers {
+		if address == multisigAddr {
+			return types.Signers{{Address: signer1, Weight: 50}, {Address: signer2, Weight: 40}, {Address: signer3, Weight: 60}}
+		}
+		return nil
+	}
+	var submitted []*types.Transaction
+	pool := NewMultisigPool(loadSigners, func(tx *types.Transaction) error {
+		submitted = append(submitted, tx)
+		return nil
+	})
+
+	expiration := uint64(time.Now().Unix() + 100)
+	tx := types.NewTransaction(multisigAddr, common.HexToAddress("0x01"), big.NewInt(100), 21000, params.MinGasPrice, nil, params.OrdinaryTx, chainID, expiration, "", "")
+	hash := testSigner.Hash(tx)
+
+	// invalid proposals
+	_, err := pool.Propose(tx)
+	assert.Equal(t, ErrMultisigNotSigned, err)
+	normalTx := types.NewTransaction(normalAddr, common.HexToAddress("0x01"), big.NewInt(100), 21000, params.MinGasPrice, nil, params.OrdinaryTx, chainID, expiration, "", "")
+	_, err = pool.Propose(normalTx.WithSigs([][]byte{signSig(t, normalTx, private1)}))
+	assert.Equal(t, ErrNotMultisigAccount, err)
+	reimbursementTx := types.NewReimbursementTransaction(multisigAddr, common.HexToAddress("0x01"), normalAddr, big.NewInt(100), nil, params.OrdinaryTx, chainID, expiration, "", "")
+	_, err = pool.Propose(reimbursementTx.WithSigs([][]byte{signSig(t, reimbursementTx, private1)}))
+	assert.Equal(t, ErrMultisigGasPayer, err)
+	_, err = pool.Propose(tx.WithSigs([][]byte{signSig(t, tx, outsider)}))
+	assert.Equal(t, ErrNotSigner, err)
+	assert.Empty(t, pool.Get(multisigAddr))
+
+	// propose with a signature
+	record, err := pool.Propose(tx.WithSigs([][]byte{signSig(t, tx, private1)}))
+	assert.NoError(t, err)
+	assert.Equal(t, hash, record.Hash)
+	assert.Equal(t, []common.Address{signer1}, record.Signers)
+	assert.Equal(t, hexutil.Uint64(50), record.Weight)
+	assert.Nil(t, record.TxHash)
+	assert.Equal(t, 1, len(pool.Get(multisigAddr)))
+	assert.Empty(t, pool.Get(normalAddr))
+
+	// add invalid signatures
+	_, err = pool.AddSignature(common.Hash{}, signSig(t, tx, private2))
+	assert.Equal(t, ErrMultisigNotFound, err)
+	_, err = pool.AddSignature(hash, signSig(t, tx, private1))
+	assert.Equal(t, ErrSignerIsExist, err)
+	_, err = pool.AddSignature(hash, signSig(t, tx, outsider))
+	assert.Equal(t, ErrNotSigner, err)
+	_, err = pool.AddSignature(hash, []byte{1, 2, 3})
+	assert.Error(t, err)
+
+	// propose again with the same signature and a new one
+	record, err = pool.Propose(tx.WithSigs([][]byte{signSig(t, tx, private1), signSig(t, tx, private2)}))
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{signer1, signer2}, record.Signers)
+	assert.Equal(t, hexutil.Uint64(90), record.Weight)
+	assert.Empty(t, submitted)
+
+	// reach the threshold
+	record, err = pool.AddSignature(hash, signSig(t, tx, private3))
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(150), record.Weight)
+	assert.Equal(t, 1, len(submitted))
+	assert.Equal(t, submitted[0].Hash(), *record.TxHash)
+	signers, err := testSigner.GetSigners(submitted[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{signer1, signer2, signer3}, signers)
+	assert.Empty(t, pool.Get(multisigAddr))
+
+	// pool is full
+	pool.Limit = 0
+	_, err = pool.Propose(tx.WithSigs([][]byte{signSig(t, tx, private1)}))
+	assert.Equal(t, ErrMultisigPoolFull, err)
+}
+
+func TestMultisigPool_limit(t *testing.T) {
+	multisigAddr1 := common.HexToAddress("0x1234")
+	multisigAddr2 := common.HexToAddress("0x5678")
+	private, _ := crypto.GenerateKey()
+	loadSigners := func(address common.Address) types.Signers {
+		return types.Signers{{Address: crypto.PubkeyToAddress(private.PublicKey), Weight: 50}}
+	}
+	pool := NewMultisigPool(loadSigners, func(tx *types.Transaction) error { return nil })
+	pool.Limit = 3
+	pool.AccountLimit = 2
+	now := time.Now().Unix()
+	propose := func(from common.Address, expiration int64) (*MultisigTx, error) {
+		tx := types.NewTransaction(from, common.HexToAddress("0x01"), big.NewInt(100), 21000, params.MinGasPrice, nil, params.OrdinaryTx, chainID, uint64(expiration), "", "")
+		return pool.Propose(tx.WithSigs([][]byte{signSig(t, tx, private)}))
+	}
+
+	// account limit
+	record1, err := propose(multisigAddr1, now+100)
+	assert.NoError(t, err)
+	_, err = propose(multisigAddr1, now+200)
+	assert.NoError(t, err)
+	_, err = propose(multisigAddr1, now+300)
+	assert.Equal(t, ErrMultisigAccountFull, err)
+	assert.Equal(t, 2, len(pool.Get(multisigAddr1)))
+
+	// the earliest expired transaction is evicted if the pool is full
+	_, err = propose(multisigAddr2, now+400)
+	assert.NoError(t, err)
+	// an invalid proposal doesn't evict any transaction
+	outsider, _ := crypto.GenerateKey()
+	invalidTx := types.NewTransaction(multisigAddr2, common.HexToAddress("0x01"), big.NewInt(100), 21000, params.MinGasPrice, nil, params.OrdinaryTx, chainID, uint64(now+500), "", "")
+	_, err = pool.Propose(invalidTx.WithSigs([][]byte{signSig(t, invalidTx, outsider)}))
+	assert.Equal(t, ErrNotSigner, err)
+	assert.Equal(t, 2, len(pool.Get(multisigAddr1)))
+	_, err = propose(multisigAddr2, now+500)
+	assert.NoError(t, err)
+	pending := pool.Get(multisigAddr1)
+	assert.Equal(t, 1, len(pending))
+	assert.NotEqual(t, record1.Hash, pending[0].Hash)
+	assert.Equal(t, 2, len(pool.Get(multisigAddr2)))
+}
+
+func TestMultisigPool_submitFail(t *testing.T) {
+	multisigAddr := common.HexToAddress("0x1234")
+	private, _ := crypto.GenerateKey()
+	loadSigners := func(address common.Address) types.Signers {
+		return types.Signers{{Address: crypto.PubkeyToAddress(private.PublicKey), Weight: 100}}
+	}
+	submitErr := ErrTxPoolFull
+	pool := NewMultisigPool(loadSigners, func(tx *types.Transaction) error { return submitErr })
+	tx := types.NewTransaction(multisigAddr, common.HexToAddress("0x01"), big.NewInt(100), 21000, params.MinGasPrice, nil, params.OrdinaryTx, chainID, uint64(time.Now().Unix()+100), "", "")
+	signedTx := tx.WithSigs([][]byte{signSig(t, tx, private)})
+
+	// the record is kept and returned with the error
+	record, err := pool.Propose(signedTx)
+	assert.Equal(t, ErrTxPoolFull, err)
+	assert.Equal(t, hexutil.Uint64(100), record.Weight)
+	assert.Nil(t, record.TxHash)
+	assert.Equal(t, 1, len(pool.Get(multisigAddr)))
+
+	// submit again by proposing the same transaction
+	submitErr = nil
+	record, err = pool.Propose(signedTx)
+	assert.NoError(t, err)
+	assert.NotNil(t, record.TxHash)
+	assert.Empty(t, pool.Get(multisigAddr))
+}
@@ -314,6 +314,27 @@ func (recent *RecentTx) RecvTx(tx *types.Transaction) {
 	recent.add(common.Hash{}, -1, tx) // 没在块上的交易， 设置其高度为-1
 }
 
+/* 交易被交易池淘汰，删除其还没有被打包的记录，使该交易可以被重新发送 */
+func (recent *RecentTx) DelUnpackedTx(tx *types.Transaction) {
+	if tx == nil {
+		return
+	}
+
+	hashes := []common.Hash{tx.Hash()}
+	if tx.Type() == params.BoxTx {
+		if box, err := types.GetBox(tx.Data()); err == nil {
+			for _, v := range box.SubTxList {
+				hashes = append(hashes, v.Hash())
+			}
+		}
+	}
+	for _, hash := range hashes {
+		if trace, ok := recent.TraceMap[hash]; ok && len(trace) <= 0 {
+			delete(recent.TraceMap, hash)
+		}
+	}
+}
+
 /* 收到一个新块，把该块种的交易放入最近交易列表 */
 func (recent *RecentTx) RecvBlock(hash common.Hash, height int64, txs []*types.Transaction) {
 	if (height < -1) || (len(txs) <= 0) {
@@ -1,15 +1,39 @@
 package txpool
 
 import (
+	"bytes"
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/metrics"
+	"math/big"
+	"sort"
 	"sync"
+	"time"
+)
+
+const (
+	DefaultGlobalTxLimit = 40000 // 交易池中默认最多保存的交易数量
+	DefaultSenderTxLimit = 1000  // 交易池中默认每个账户最多保存的交易数量
+)
+
+// JournalRotateInterval is the interval to compact the tx pool journal
+var JournalRotateInterval = time.Hour
+
+var (
+	ErrTxIsNil          = errors.New("the transaction is nil")
+	ErrTxIsExist        = errors.New("the transaction is already exist")
+	ErrTxGasPriceTooLow = errors.New("the gas price of transaction is lower than the least gas price")
+	ErrTxPoolFull       = errors.New("the tx pool is full")
+	ErrSenderTxsFull    = errors.New("the sender has too many transactions in tx pool")
 )
 
 var (
 	txpoolTotalNumberCounter = metrics.NewCounter(metrics.TxpoolNumber_counterName) // 交易池中剩下的总交易数量
+	rejectedTxMeter          = metrics.NewMeter(metrics.RejectedTx_meterName)       // 被交易池拒收的交易
+	evictedTxMeter           = metrics.NewMeter(metrics.EvictedTx_meterName)        // 被交易池淘汰的交易
 	blockTradeAmount         = common.Lemo2Mo("500000")                             // 如果交易的amount 大于此值则进行事件通知
 )
 
@@ -23,22 +47,79 @@ type TxPool struct {
 	/* 从当前高度向后的3600个块 */
 	BlockCache *BlocksTrie
 
+	/* 最近被删除的交易及原因 */
+	DroppedTxs *DroppedTxs
+
+	/* 为true时按gas price从高到低打包交易，否则按收到的顺序打包 */
+	GasPricePriority bool
+
+	/* 交易池中最多保存的交易数量，以及每个账户最多保存的交易数量 */
+	GlobalLimit int
+	SenderLimit int
+
+	/* 交易池的磁盘日志 */
+	journal *txJournal
+	quit    chan struct{}
+
 	RW sync.RWMutex
 }
 
 func NewTxPool() *TxPool {
 	return &TxPool{
-		PendingTxs: NewTxQueue(),
-		RecentTxs:  NewTxRecently(),
-		BlockCache: NewBlocksTrie(),
+		PendingTxs:  NewTxQueue(),
+		RecentTxs:   NewTxRecently(),
+		BlockCache:  NewBlocksTrie(),
+		DroppedTxs:  NewDroppedTxs(),
+		GlobalLimit: DefaultGlobalTxLimit,
+		SenderLimit: DefaultSenderTxLimit,
 	}
 }
 
 /* 本节点出块时，从交易池中取出交易进行打包，但并不从交易池中删除 */
 func (pool *TxPool) Get(time uint32, size int) []*types.Transaction {
+	pool.RW.RLock()
+	defer pool.RW.RUnlock()
+	if pool.GasPricePriority {
+		return pool.PendingTxs.PopByPrice(time, size)
+	}
+	return pool.PendingTxs.Peek(time, size)
+}
+
+// Prune 本节点出块前清理交易池，删除超时的交易并记录下来
+func (pool *TxPool) Prune(time uint32) {
 	pool.RW.Lock()
 	defer pool.RW.Unlock()
-	return pool.PendingTxs.Pop(time, size)
+	pool.purge(time)
+}
+
+// PackingGasPrice 下一个区块能打包的交易的最低gas price。如果交易池中的交易不能填满一个区块，则返回params.MinGasPrice
+func (pool *TxPool) PackingGasPrice(time uint32) *big.Int {
+	pool.RW.RLock()
+	defer pool.RW.RUnlock()
+
+	if !pool.GasPricePriority {
+		return new(big.Int).Set(params.MinGasPrice)
+	}
+	txs := pool.PendingTxs.PopByPrice(time, params.MaxTxsForMiner)
+	var (
+		gasLimit   uint64
+		leastPrice *big.Int
+		isFull     = len(txs) >= params.MaxTxsForMiner
+	)
+	for _, tx := range txs {
+		gasLimit += tx.GasLimit()
+		if gasLimit > params.TargetGasLimit {
+			isFull = true
+			break
+		}
+		if leastPrice == nil || tx.GasPrice().Cmp(leastPrice) < 0 {
+			leastPrice = tx.GasPrice()
+		}
+	}
+	if !isFull || leastPrice == nil {
+		return new(big.Int).Set(params.MinGasPrice)
+	}
+	return leastPrice
 }
 
 // ExistCanPackageTx 存在可以打包的交易
@@ -48,8 +129,8 @@ func (pool *TxPool) ExistCanPackageTx(time uint32) bool {
 	return pool.PendingTxs.IsExistCanPackageTx(time)
 }
 
-/* 本节点出块时，执行交易后，发现错误的交易通过该接口进行删除 */
-func (pool *TxPool) DelInvalidTxs(txs []*types.Transaction) {
+/* 本节点出块时，执行交易后，发现错误的交易通过该接口进行删除。reasons是对应交易的错误原因 */
+func (pool *TxPool) DelInvalidTxs(txs []*types.Transaction, reasons []error) {
 	pool.RW.Lock()
 	defer pool.RW.Unlock()
 
@@ -57,13 +138,49 @@ func (pool *TxPool) DelInvalidTxs(txs []*types.Transaction) {
 		return
 	}
 
+	now := uint64(time.Now().Unix())
+	pool.DroppedTxs.Prune(now)
 	hashes := make([]common.Hash, 0, len(txs))
-	for _, tx := range txs {
+	for i, tx := range txs {
 		hashes = append(hashes, tx.Hash())
+		reason := "invalid transaction"
+		if i < len(reasons) && reasons[i] != nil {
+			reason = reasons[i].Error()
+		}
+		pool.DroppedTxs.Add(tx.Hash(), TxStatusDropped, reason, now)
 	}
 	pool.PendingTxs.DelBatch(hashes)
 }
 
+// GetTxStatus 查询交易在交易池中的状态
+func (pool *TxPool) GetTxStatus(hash common.Hash, time uint32) *TxStatus {
+	pool.RW.RLock()
+	defer pool.RW.RUnlock()
+
+	if trace := pool.RecentTxs.TraceMap[hash]; len(trace) > 0 {
+		blockHashes := make([]common.Hash, 0, len(trace))
+		for blockHash := range trace {
+			blockHashes = append(blockHashes, blockHash)
+		}
+		sort.Slice(blockHashes, func(i, j int) bool {
+			return bytes.Compare(blockHashes[i][:], blockHashes[j][:]) < 0
+		})
+		return &TxStatus{Status: TxStatusPacked, BlockHashes: blockHashes}
+	}
+
+	if tx := pool.PendingTxs.Get(hash); tx != nil {
+		if pool.PendingTxs.isTimeOut(tx, time) {
+			return &TxStatus{Status: TxStatusExpired}
+		}
+		return &TxStatus{Status: TxStatusPending}
+	}
+
+	if record, ok := pool.DroppedTxs.Get(hash); ok && record.Time+DroppedTxKeepTime >= uint64(time) {
+		return &TxStatus{Status: record.Status, Reason: record.Reason}
+	}
+	return &TxStatus{Status: TxStatusUnknown}
+}
+
 func (pool *TxPool) isInBlocks(hashes HashSet, blocks []*TrieNode) bool {
 	if len(hashes) <= 0 || len(blocks) <= 0 {
 		return false
@@ -157,29 +274,94 @@ func (pool *TxPool) RecvBlock(block *types.Block) {
 
 /* 收到一笔新的交易 */
 func (pool *TxPool) RecvTx(tx *types.Transaction) bool {
+	return pool.AddTx(tx) == nil
+}
+
+// AddTx 收到一笔新的交易，返回交易被拒收的原因
+func (pool *TxPool) AddTx(tx *types.Transaction) error {
 	pool.RW.Lock()
 	defer pool.RW.Unlock()
 
 	if tx == nil {
-		return false
+		return ErrTxIsNil
 	}
 
 	isExist := pool.RecentTxs.IsExist(tx)
 	if isExist {
 		log.Debug("tx is already exist. hash: " + tx.Hash().Hex())
-		return false
-	} else {
-		pool.RecentTxs.RecvTx(tx)
-		pool.PendingTxs.Push(tx)
-		txpoolTotalNumberCounter.Inc(1) // 记录收到一笔交易
-		if tx.Amount().Cmp(blockTradeAmount) >= 0 {
-			toString := "[nil]"
-			if tx.To() != nil {
-				toString = tx.To().String()
-			}
-			log.Eventf(log.TxEvent, "Block trade appear. %s send %s to %s", tx.From().String(), tx.Amount().String(), toString)
+		return ErrTxIsExist
+	}
+	if err := pool.makeRoom(tx); err != nil {
+		log.Debugf("tx is rejected. hash: %s, err: %v", tx.Hash().Hex(), err)
+		rejectedTxMeter.Mark(1)
+		return err
+	}
+
+	pool.RecentTxs.RecvTx(tx)
+	pool.PendingTxs.Push(tx)
+	txpoolTotalNumberCounter.Inc(1) // 记录收到一笔交易
+	if pool.journal != nil {
+		if err := pool.journal.insert(tx); err != nil {
+			log.Warnf("Failed to journal transaction. hash: %s, err: %v", tx.Hash().Hex(), err)
 		}
-		return true
+	}
+	if tx.Amount().Cmp(blockTradeAmount) >= 0 {
+		toString := "[nil]"
+		if tx.To() != nil {
+			toString = tx.To().String()
+		}
+		log.Eventf(log.TxEvent, "Block trade appear. %s send %s to %s", tx.From().String(), tx.Amount().String(), toString)
+	}
+	return nil
+}
+
+// makeRoom 检查交易是否可以进入交易池。交易池或发送者的交易已满时，淘汰gas price比新交易低的交易中最便宜且最早收到的一笔
+func (pool *TxPool) makeRoom(tx *types.Transaction) error {
+	if tx.GasPrice().Cmp(params.MinGasPrice) < 0 {
+		return ErrTxGasPriceTooLow
+	}
+
+	from := tx.From()
+	senderFull := pool.SenderLimit > 0 && pool.PendingTxs.SenderLen(from) >= pool.SenderLimit
+	poolFull := pool.GlobalLimit > 0 && pool.PendingTxs.Len() >= pool.GlobalLimit
+	// 被软删除的交易仍然留在队列中
+	bloated := pool.GlobalLimit > 0 && len(pool.PendingTxs.TxsQueue) >= 2*pool.GlobalLimit
+	if senderFull || poolFull || bloated {
+		// 只有出块节点会通过Get清理交易池，所以在这里清理已删除和超时的交易，以免交易池被超时的交易占满
+		pool.purge(uint32(time.Now().Unix()))
+	}
+	if pool.SenderLimit > 0 && pool.PendingTxs.SenderLen(from) >= pool.SenderLimit {
+		cheapest := pool.PendingTxs.Cheapest(&from)
+		if cheapest == nil || cheapest.GasPrice().Cmp(tx.GasPrice()) >= 0 {
+			return ErrSenderTxsFull
+		}
+		pool.evict(cheapest)
+	}
+	if pool.GlobalLimit > 0 && pool.PendingTxs.Len() >= pool.GlobalLimit {
+		cheapest := pool.PendingTxs.Cheapest(nil)
+		if cheapest == nil || cheapest.GasPrice().Cmp(tx.GasPrice()) >= 0 {
+			return ErrTxPoolFull
+		}
+		pool.evict(cheapest)
+	}
+	return nil
+}
+
+// evict 从交易池中淘汰交易，并记录淘汰原因
+func (pool *TxPool) evict(tx *types.Transaction) {
+	now := uint64(time.Now().Unix())
+	pool.PendingTxs.Remove(tx.Hash())
+	pool.RecentTxs.DelUnpackedTx(tx)
+	pool.DroppedTxs.Add(tx.Hash(), TxStatusDropped, "evicted by the transaction with higher gas price", now)
+	evictedTxMeter.Mark(1)
+	log.Debugf("tx is evicted from tx pool. hash: %s", tx.Hash().Hex())
+}
+
+// purge 删除超时的交易并记录下来，已删除的交易过多时从队列中彻底删除它们
+func (pool *TxPool) purge(time uint32) {
+	pool.DroppedTxs.Prune(uint64(time))
+	for _, hash := range pool.PendingTxs.Compact(time) {
+		pool.DroppedTxs.Add(hash, TxStatusExpired, "", uint64(time))
 	}
 }
 
@@ -225,3 +407,71 @@ func (pool *TxPool) PruneBlock(block *types.Block) {
 
 	pool.BlockCache.DelBlock(block)
 }
+
+// LoadJournal 从磁盘日志中恢复交易池中的交易，交易需要重新通过验证。之后定时压缩日志
+func (pool *TxPool) LoadJournal(path string, chainID uint16) error {
+	journal := newTxJournal(path)
+	total, dropped, err := journal.load(func(tx *types.Transaction) error {
+		if err := tx.VerifyTxBody(chainID, uint64(time.Now().Unix()), false); err != nil {
+			return err
+		}
+		return pool.AddTx(tx)
+	})
+	if err != nil {
+		log.Warnf("Failed to load tx pool journal: %v", err)
+	}
+	log.Infof("Loaded tx pool journal. total: %d, dropped: %d", total, dropped)
+
+	pool.RW.Lock()
+	pool.journal = journal
+	pool.RW.Unlock()
+	if err := pool.RotateJournal(); err != nil {
+		return err
+	}
+	pool.quit = make(chan struct{})
+	go pool.journalLoop(pool.quit)
+	return nil
+}
+
+// RotateJournal 用交易池中还没有超时的交易重新生成日志
+func (pool *TxPool) RotateJournal() error {
+	pool.RW.Lock()
+	defer pool.RW.Unlock()
+
+	if pool.journal == nil {
+		return ErrNoActiveJournal
+	}
+	return pool.journal.rotate(pool.PendingTxs.Lives(uint32(time.Now().Unix())))
+}
+
+func (pool *TxPool) journalLoop(quit chan struct{}) {
+	ticker := time.NewTicker(JournalRotateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pool.RotateJournal(); err != nil {
+				log.Warnf("Failed to rotate tx pool journal: %v", err)
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// Stop 停止压缩日志并关闭日志文件
+func (pool *TxPool) Stop() {
+	pool.RW.Lock()
+	defer pool.RW.Unlock()
+
+	if pool.quit != nil {
+		close(pool.quit)
+		pool.quit = nil
+	}
+	if pool.journal != nil {
+		if err := pool.journal.close(); err != nil {
+			log.Errorf("Failed to close tx pool journal: %v", err)
+		}
+		pool.journal = nil
+	}
+}
@@ -1,11 +1,16 @@
 package txpool
 
 import (
+	"crypto/ecdsa"
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/store"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -28,6 +33,84 @@ func TestTxPool_RecvTx(t *testing.T) {
 	assert.Equal(t, 3, len(result))
 }
 
+func TestTxPool_AddTx(t *testing.T) {
+	now := time.Now().Unix()
+	privateA, _ := crypto.GenerateKey()
+	privateB, _ := crypto.GenerateKey()
+	makePricedTx := func(private *ecdsa.PrivateKey, to string, gasPrice int64) *types.Transaction {
+		return makeTransaction(private, common.HexToAddress(to), params.OrdinaryTx, common.Big1, big.NewInt(gasPrice), uint64(now+100), 1000000)
+	}
+	pool := NewTxPool()
+	pool.GlobalLimit = 3
+	pool.SenderLimit = 2
+
+	// exist
+	txA1 := makePricedTx(privateA, "0x01", 2000000000)
+	assert.NoError(t, pool.AddTx(txA1))
+	assert.Equal(t, ErrTxIsExist, pool.AddTx(txA1))
+	assert.Equal(t, ErrTxIsNil, pool.AddTx(nil))
+
+	// gas price is too low
+	assert.Equal(t, ErrTxGasPriceTooLow, pool.AddTx(makePricedTx(privateA, "0x02", 1)))
+
+	// the sender's transactions are full
+	txA2 := makePricedTx(privateA, "0x03", 3000000000)
+	assert.NoError(t, pool.AddTx(txA2))
+	assert.Equal(t, ErrSenderTxsFull, pool.AddTx(makePricedTx(privateA, "0x04", 2000000000)))
+	txA3 := makePricedTx(privateA, "0x05", 4000000000)
+	assert.NoError(t, pool.AddTx(txA3))
+	status := pool.GetTxStatus(txA1.Hash(), uint32(now))
+	assert.Equal(t, TxStatusDropped, status.Status)
+	assert.NotEmpty(t, status.Reason)
+	assert.Equal(t, 2, pool.PendingTxs.SenderLen(txA1.From()))
+
+	// the pool is full
+	txB1 := makePricedTx(privateB, "0x06", 2000000000)
+	assert.NoError(t, pool.AddTx(txB1))
+	assert.Equal(t, ErrTxPoolFull, pool.AddTx(makePricedTx(privateB, "0x07", 2000000000)))
+	txB2 := makePricedTx(privateB, "0x08", 5000000000)
+	assert.NoError(t, pool.AddTx(txB2))
+	assert.Equal(t, TxStatusDropped, pool.GetTxStatus(txB1.Hash(), uint32(now)).Status)
+	assert.Equal(t, 3, pool.PendingTxs.Len())
+
+	// the evicted transaction can be received again
+	assert.Equal(t, ErrSenderTxsFull, pool.AddTx(txA1))
+	pool.SenderLimit = 3
+	assert.False(t, pool.RecvTx(txA1))
+	pool.GlobalLimit = 4
+	assert.True(t, pool.RecvTx(txA1))
+	assert.Equal(t, TxStatusPending, pool.GetTxStatus(txA1.Hash(), uint32(now)).Status)
+}
+
+func TestTxPool_AddTx_expired(t *testing.T) {
+	now := time.Now().Unix()
+	pool := NewTxPool()
+	pool.GlobalLimit = 2
+
+	// the pool is full of timeout transactions
+	tx1 := makeTx(common.HexToAddress("0x01"), now-100)
+	tx2 := makeTx(common.HexToAddress("0x02"), now-100)
+	assert.NoError(t, pool.AddTx(tx1))
+	assert.NoError(t, pool.AddTx(tx2))
+	assert.Equal(t, 2, pool.PendingTxs.Len())
+
+	// new transactions are accepted without calling Get
+	tx3 := makeTx(common.HexToAddress("0x03"), now+100)
+	tx4 := makeTx(common.HexToAddress("0x04"), now+100)
+	assert.NoError(t, pool.AddTx(tx3))
+	assert.NoError(t, pool.AddTx(tx4))
+	assert.Equal(t, 2, pool.PendingTxs.Len())
+	assert.Equal(t, 2, len(pool.PendingTxs.TxsQueue))
+	assert.Equal(t, TxStatusExpired, pool.GetTxStatus(tx1.Hash(), uint32(now)).Status)
+	assert.Equal(t, TxStatusPending, pool.GetTxStatus(tx3.Hash(), uint32(now)).Status)
+
+	// the evicted transaction is removed from queue
+	tx5 := makeTransaction(testPrivate, common.HexToAddress("0x05"), params.OrdinaryTx, common.Big1, new(big.Int).Add(params.MinGasPrice, common.Big1), uint64(now+100), 1000000)
+	assert.NoError(t, pool.AddTx(tx5))
+	assert.Equal(t, 2, len(pool.PendingTxs.TxsQueue))
+	assert.Equal(t, TxStatusDropped, pool.GetTxStatus(tx3.Hash(), uint32(now)).Status)
+}
+
 func TestTxPool_DelInvalidTxs(t *testing.T) {
 	curTime := time.Now().Unix()
 	pool := NewTxPool()
@@ -45,12 +128,76 @@ func TestTxPool_DelInvalidTxs(t *testing.T) {
 	delTxs = append(delTxs, tx1)
 	delTxs = append(delTxs, tx2)
 	delTxs = append(delTxs, tx3)
-	pool.DelInvalidTxs(delTxs)
+	pool.DelInvalidTxs(delTxs, nil)
 
 	result := pool.Get(uint32(curTime), 10)
 	assert.Equal(t, 1, len(result))
 }
 
+func TestTxPool_GetTxStatus(t *testing.T) {
+	curTime := time.Now().Unix()
+	pool := NewTxPool()
+
+	pendingTx := makeTxRandom(common.HexToAddress("0x01"))
+	invalidTx := makeTxRandom(common.HexToAddress("0x02"))
+	expiredTx := makeTx(common.HexToAddress("0x03"), curTime+10)
+	packedTx := makeTxRandom(common.HexToAddress("0x04"))
+	pool.RecvTxs(types.Transactions{pendingTx, invalidTx, expiredTx, packedTx})
+
+	block := store.GetBlock1()
+	block.Header.Time = uint32(curTime)
+	block.Txs = types.Transactions{packedTx}
+	pool.RecvBlock(block)
+	pool.DelInvalidTxs(types.Transactions{invalidTx}, []error{errors.New("insufficient balance")})
+
+	now := uint32(curTime)
+	assert.Equal(t, &TxStatus{Status: TxStatusPending}, pool.GetTxStatus(pendingTx.Hash(), now))
+	assert.Equal(t, &TxStatus{Status: TxStatusDropped, Reason: "insufficient balance"}, pool.GetTxStatus(invalidTx.Hash(), now))
+	assert.Equal(t, &TxStatus{Status: TxStatusPending}, pool.GetTxStatus(expiredTx.Hash(), now))
+	assert.Equal(t, &TxStatus{Status: TxStatusPacked, BlockHashes: []common.Hash{block.Hash()}}, pool.GetTxStatus(packedTx.Hash(), now))
+	assert.Equal(t, &TxStatus{Status: TxStatusUnknown}, pool.GetTxStatus(common.HexToHash("0x123"), now))
+
+	// expired
+	now = uint32(curTime + 20)
+	assert.Equal(t, &TxStatus{Status: TxStatusExpired}, pool.GetTxStatus(expiredTx.Hash(), now))
+	result := pool.Get(now, 10)
+	assert.Equal(t, types.Transactions{pendingTx}, types.Transactions(result))
+	// Get doesn't change the pool
+	assert.NotNil(t, pool.PendingTxs.Get(expiredTx.Hash()))
+	pool.Prune(now)
+	assert.Nil(t, pool.PendingTxs.Get(expiredTx.Hash()))
+	assert.Equal(t, &TxStatus{Status: TxStatusExpired}, pool.GetTxStatus(expiredTx.Hash(), now))
+
+	// the drop records are removed after a while
+	now = uint32(curTime + int64(DroppedTxKeepTime) + 30)
+	assert.Equal(t, &TxStatus{Status: TxStatusUnknown}, pool.GetTxStatus(invalidTx.Hash(), now))
+	assert.Equal(t, &TxStatus{Status: TxStatusUnknown}, pool.GetTxStatus(expiredTx.Hash(), now))
+}
+
+func TestTxPool_PackingGasPrice(t *testing.T) {
+	now := time.Now().Unix()
+	pool := NewTxPool()
+	tx1 := makeTransaction(testPrivate, common.HexToAddress("0x01"), params.OrdinaryTx, common.Big1, big.NewInt(3000000000), uint64(now+100), 1000000)
+	tx2 := makeTransaction(testPrivate, common.HexToAddress("0x02"), params.OrdinaryTx, common.Big1, big.NewInt(2000000000), uint64(now+100), 1000000)
+	tx3 := makeTransaction(testPrivate, common.HexToAddress("0x03"), params.OrdinaryTx, common.Big1, big.NewInt(4000000000), uint64(now+100), 1000000)
+	pool.RecvTxs(types.Transactions{tx1, tx2, tx3})
+
+	// arrival order
+	assert.Equal(t, types.Transactions{tx1, tx2, tx3}, types.Transactions(pool.Get(uint32(now), 10)))
+	assert.Equal(t, params.MinGasPrice, pool.PackingGasPrice(uint32(now)))
+
+	// gas price order
+	pool.GasPricePriority = true
+	assert.Equal(t, types.Transactions{tx3, tx1, tx2}, types.Transactions(pool.Get(uint32(now), 10)))
+	// the pool is not full
+	assert.Equal(t, params.MinGasPrice, pool.PackingGasPrice(uint32(now)))
+	// the pool is full
+	oldMaxTxs := params.MaxTxsForMiner
+	params.MaxTxsForMiner = 2
+	defer func() { params.MaxTxsForMiner = oldMaxTxs }()
+	assert.Equal(t, big.NewInt(3000000000), pool.PackingGasPrice(uint32(now)))
+}
+
 func TestTxPool_RecvBlock(t *testing.T) {
 	curTime := time.Now().Unix()
 
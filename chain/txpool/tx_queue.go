@@ -1,22 +1,163 @@
 package txpool
 
 import (
+	"container/heap"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"sort"
 )
 
+// txEntry 队列中的交易，以及它在各个堆中的位置
+type txEntry struct {
+	tx         *types.Transaction
+	seq        uint64 // 收到交易的顺序
+	expiration uint64
+	index      [3]int
+}
+
+const (
+	priceSlot = iota
+	senderPriceSlot
+	expirationSlot
+)
+
+// txHeap 未被删除的交易组成的最小堆，实现了heap.Interface
+type txHeap struct {
+	slot    int
+	entries []*txEntry
+	less    func(a, b *txEntry) bool
+}
+
+func newTxHeap(slot int, less func(a, b *txEntry) bool) *txHeap {
+	return &txHeap{slot: slot, entries: make([]*txEntry, 0), less: less}
+}
+
+func (h *txHeap) Len() int { return len(h.entries) }
+
+func (h *txHeap) Less(i, j int) bool { return h.less(h.entries[i], h.entries[j]) }
+
+func (h *txHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index[h.slot] = i
+	h.entries[j].index[h.slot] = j
+}
+
+func (h *txHeap) Push(x interface{}) {
+	entry := x.(*txEntry)
+	entry.index[h.slot] = len(h.entries)
+	h.entries = append(h.entries, entry)
+}
+
+func (h *txHeap) Pop() interface{} {
+	last := len(h.entries) - 1
+	entry := h.entries[last]
+	h.entries[last] = nil
+	h.entries = h.entries[:last]
+	entry.index[h.slot] = -1
+	return entry
+}
+
+func (h *txHeap) peek() *txEntry {
+	if len(h.entries) == 0 {
+		return nil
+	}
+	return h.entries[0]
+}
+
+// cheaper gas price更低的交易排在前面，价格相同时更早收到的交易排在前面
+func cheaper(a, b *txEntry) bool {
+	if cmp := a.tx.GasPrice().Cmp(b.tx.GasPrice()); cmp != 0 {
+		return cmp < 0
+	}
+	return a.seq < b.seq
+}
+
+// expireEarlier 更早超时的交易排在前面
+func expireEarlier(a, b *txEntry) bool {
+	if a.expiration != b.expiration {
+		return a.expiration < b.expiration
+	}
+	return a.seq < b.seq
+}
+
 type TxQueue struct {
 	/* 交易状态标记，true：正常；false：已删除 */
 	TxsStatus map[common.Hash]bool
 
 	TxsQueue []*types.Transaction
+
+	/* 队列中所有交易的索引 */
+	entries map[common.Hash]*txEntry
+	nextSeq uint64
+
+	/* 未被删除的交易按gas price和超时时间排序，用于淘汰交易和删除超时的交易 */
+	byPrice       *txHeap
+	bySenderPrice map[common.Address]*txHeap
+	byExpiration  *txHeap
 }
 
 func NewTxQueue() *TxQueue {
 	return &TxQueue{
-		TxsStatus: make(map[common.Hash]bool),
-		TxsQueue:  make([]*types.Transaction, 0),
+		TxsStatus:     make(map[common.Hash]bool),
+		TxsQueue:      make([]*types.Transaction, 0),
+		entries:       make(map[common.Hash]*txEntry),
+		byPrice:       newTxHeap(priceSlot, cheaper),
+		bySenderPrice: make(map[common.Address]*txHeap),
+		byExpiration:  newTxHeap(expirationSlot, expireEarlier),
+	}
+}
+
+// addLive 将未被删除的交易加入各个堆
+func (queue *TxQueue) addLive(entry *txEntry) {
+	heap.Push(queue.byPrice, entry)
+	from := entry.tx.From()
+	senderHeap, ok := queue.bySenderPrice[from]
+	if !ok {
+		senderHeap = newTxHeap(senderPriceSlot, cheaper)
+		queue.bySenderPrice[from] = senderHeap
+	}
+	heap.Push(senderHeap, entry)
+	heap.Push(queue.byExpiration, entry)
+}
+
+// removeLive 将被删除的交易移出各个堆
+func (queue *TxQueue) removeLive(entry *txEntry) {
+	heap.Remove(queue.byPrice, entry.index[priceSlot])
+	from := entry.tx.From()
+	senderHeap := queue.bySenderPrice[from]
+	heap.Remove(senderHeap, entry.index[senderPriceSlot])
+	if senderHeap.Len() == 0 {
+		delete(queue.bySenderPrice, from)
+	}
+	heap.Remove(queue.byExpiration, entry.index[expirationSlot])
+}
+
+// Len 未被删除的交易数量
+func (queue *TxQueue) Len() int {
+	return queue.byPrice.Len()
+}
+
+// SenderLen 发送者未被删除的交易数量
+func (queue *TxQueue) SenderLen(from common.Address) int {
+	if senderHeap, ok := queue.bySenderPrice[from]; ok {
+		return senderHeap.Len()
+	}
+	return 0
+}
+
+// Cheapest 返回gas price最低的未被删除的交易，价格相同时返回最早收到的交易。from不为nil时只在该发送者的交易中查找
+func (queue *TxQueue) Cheapest(from *common.Address) *types.Transaction {
+	var entry *txEntry
+	if from == nil {
+		entry = queue.byPrice.peek()
+	} else if senderHeap, ok := queue.bySenderPrice[*from]; ok {
+		entry = senderHeap.peek()
+	}
+	if entry == nil {
+		return nil
 	}
+	return entry.tx
 }
 
 func (queue *TxQueue) isExist(hash common.Hash) bool {
@@ -28,30 +169,72 @@ func (queue *TxQueue) isExist(hash common.Hash) bool {
 	return ok && isExist
 }
 
-func (queue *TxQueue) hardDel(index int) {
-	tx := queue.TxsQueue[index]
-	delete(queue.TxsStatus, tx.Hash())
+// drop 删除交易的所有记录，但并不从TxsQueue中移除
+func (queue *TxQueue) drop(tx *types.Transaction) {
+	hash := tx.Hash()
+	if queue.isExist(hash) {
+		queue.removeLive(queue.entries[hash])
+	}
+	delete(queue.TxsStatus, hash)
+	delete(queue.entries, hash)
+}
 
+func (queue *TxQueue) hardDel(index int) {
+	queue.drop(queue.TxsQueue[index])
 	queue.TxsQueue = append(queue.TxsQueue[:index], queue.TxsQueue[index+1:]...)
 }
 
+// shrink 已删除的交易不少于未删除的交易时，从队列中彻底删除已删除的交易，使得整理队列的开销均摊到每次删除上
+func (queue *TxQueue) shrink() {
+	if len(queue.TxsQueue) < 2*queue.Len() {
+		return
+	}
+	txs := make([]*types.Transaction, 0, queue.Len())
+	for _, tx := range queue.TxsQueue {
+		if !queue.isExist(tx.Hash()) {
+			queue.drop(tx)
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	queue.TxsQueue = txs
+}
+
 func (queue *TxQueue) softDel(hash common.Hash) {
 	queue.TxsStatus[hash] = false
 }
 
 func (queue *TxQueue) isTimeOut(tx *types.Transaction, time uint32) bool {
-	// TODO: box tx
-	if tx.Expiration() < uint64(time) {
+	if txExpiration(tx) < uint64(time) {
 		return true
 	} else {
 		return false
 	}
 }
 
+// txExpiration 交易的超时时间。箱子交易中的子交易都必须被执行，所以取其中最早的超时时间
+func txExpiration(tx *types.Transaction) uint64 {
+	expiration := tx.Expiration()
+	if tx.Type() != params.BoxTx {
+		return expiration
+	}
+	box, err := types.GetBox(tx.Data())
+	if err != nil {
+		return expiration
+	}
+	for _, subTx := range box.SubTxList {
+		if subTx.Expiration() < expiration {
+			expiration = subTx.Expiration()
+		}
+	}
+	return expiration
+}
+
 func (queue *TxQueue) Del(hash common.Hash) {
 	if !queue.isExist(hash) {
 		return
 	} else {
+		queue.removeLive(queue.entries[hash])
 		queue.softDel(hash)
 		txpoolTotalNumberCounter.Dec(1) // 删除交易池中的交易
 	}
@@ -67,6 +250,49 @@ func (queue *TxQueue) DelBatch(hashes []common.Hash) {
 	}
 }
 
+// Get returns the transaction which is not deleted
+func (queue *TxQueue) Get(hash common.Hash) *types.Transaction {
+	if !queue.isExist(hash) {
+		return nil
+	}
+	return queue.entries[hash].tx
+}
+
+// Lives 返回所有未被删除且没有超时的交易
+func (queue *TxQueue) Lives(time uint32) []*types.Transaction {
+	result := make([]*types.Transaction, 0, queue.Len())
+	for _, tx := range queue.TxsQueue {
+		if queue.isExist(tx.Hash()) && !queue.isTimeOut(tx, time) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// DelExpired 删除所有超时的交易，返回被删除的交易hash
+func (queue *TxQueue) DelExpired(time uint32) []common.Hash {
+	result := make([]common.Hash, 0)
+	for entry := queue.byExpiration.peek(); entry != nil && entry.expiration < uint64(time); entry = queue.byExpiration.peek() {
+		hash := entry.tx.Hash()
+		queue.Del(hash)
+		result = append(result, hash)
+	}
+	return result
+}
+
+// Compact 删除超时的交易，并在已删除的交易过多时从队列中彻底删除它们，返回超时的交易hash
+func (queue *TxQueue) Compact(time uint32) []common.Hash {
+	expired := queue.DelExpired(time)
+	queue.shrink()
+	return expired
+}
+
+// Remove 删除交易，并在已删除的交易过多时从队列中彻底删除它们
+func (queue *TxQueue) Remove(hash common.Hash) {
+	queue.Del(hash)
+	queue.shrink()
+}
+
 // IsExistCanPackageTx 存在可以打包的交易
 func (queue *TxQueue) IsExistCanPackageTx(time uint32) bool {
 	for _, tx := range queue.TxsQueue {
@@ -104,6 +330,83 @@ func (queue *TxQueue) Pop(time uint32, size int) []*types.Transaction {
 	return result
 }
 
+// Peek 按收到的顺序取出未被删除且没有超时的交易，不修改队列
+func (queue *TxQueue) Peek(time uint32, size int) []*types.Transaction {
+	result := make([]*types.Transaction, 0)
+	for _, tx := range queue.TxsQueue {
+		if len(result) >= size {
+			break
+		}
+		if queue.isExist(tx.Hash()) && !queue.isTimeOut(tx, time) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// PopByPrice 按gas price从高到低取出交易，不修改队列。
+// 每一轮中每个发送者最多取一笔交易，以免一个发送者占满整个区块。gas price相同时先取更早超时的交易。
+// 箱子交易作为一个整体参与排序，其子交易不会被拆开
+func (queue *TxQueue) PopByPrice(time uint32, size int) []*types.Transaction {
+	result := make([]*types.Transaction, 0)
+	if size <= 0 {
+		return result
+	}
+
+	// skip the deleted and timeout transactions, then group the others by sender
+	type pricedTx struct {
+		tx         *types.Transaction
+		expiration uint64
+		index      int // arrival order
+	}
+	bySender := make(map[common.Address][]*pricedTx)
+	senders := make([]common.Address, 0)
+	for index, tx := range queue.TxsQueue {
+		if !queue.isExist(tx.Hash()) || queue.isTimeOut(tx, time) {
+			continue
+		}
+		from := tx.From()
+		if _, ok := bySender[from]; !ok {
+			senders = append(senders, from)
+		}
+		bySender[from] = append(bySender[from], &pricedTx{tx: tx, expiration: txExpiration(tx), index: index})
+	}
+	less := func(a, b *pricedTx) bool {
+		if cmp := a.tx.GasPrice().Cmp(b.tx.GasPrice()); cmp != 0 {
+			return cmp > 0
+		}
+		if a.expiration != b.expiration {
+			return a.expiration < b.expiration
+		}
+		return a.index < b.index
+	}
+	for _, from := range senders {
+		txs := bySender[from]
+		sort.SliceStable(txs, func(i, j int) bool { return less(txs[i], txs[j]) })
+	}
+
+	// pick the best transaction of every sender in each round
+	for round := 0; len(result) < size; round++ {
+		roundTxs := make([]*pricedTx, 0)
+		for _, from := range senders {
+			if txs := bySender[from]; round < len(txs) {
+				roundTxs = append(roundTxs, txs[round])
+			}
+		}
+		if len(roundTxs) == 0 {
+			break
+		}
+		sort.SliceStable(roundTxs, func(i, j int) bool { return less(roundTxs[i], roundTxs[j]) })
+		for _, item := range roundTxs {
+			if len(result) >= size {
+				break
+			}
+			result = append(result, item.tx)
+		}
+	}
+	return result
+}
+
 func (queue *TxQueue) Push(tx *types.Transaction) {
 	if tx == nil {
 		return
@@ -112,13 +415,18 @@ func (queue *TxQueue) Push(tx *types.Transaction) {
 	hash := tx.Hash()
 	isExist, ok := queue.TxsStatus[hash]
 	if !ok { // 没有该交易
+		entry := &txEntry{tx: tx, seq: queue.nextSeq, expiration: txExpiration(tx)}
+		queue.nextSeq++
+		queue.entries[hash] = entry
 		queue.TxsStatus[hash] = true
 		queue.TxsQueue = append(queue.TxsQueue, tx)
+		queue.addLive(entry)
 		return
 	}
 
 	if !isExist { // 有该交易，但是处于删除状态
 		queue.TxsStatus[hash] = true
+		queue.addLive(queue.entries[hash])
 		return
 	}
 }
@@ -1,9 +1,12 @@
 package txpool
 
 import (
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -149,3 +152,112 @@ func TestTxQueue_DelBatch(t *testing.T) {
 	assert.Equal(t, false, queue.TxsStatus[txs[3].Hash()])
 	assert.Equal(t, true, queue.TxsStatus[txs[4].Hash()])
 }
+
+func TestTxQueue_PopByPrice(t *testing.T) {
+	now := time.Now().Unix()
+	privateA, _ := crypto.GenerateKey()
+	privateB, _ := crypto.GenerateKey()
+	privateC, _ := crypto.GenerateKey()
+	txA1 := makeTransaction(privateA, common.HexToAddress("0x01"), params.OrdinaryTx, common.Big1, big.NewInt(4), uint64(now+100), 1000000)
+	txA2 := makeTransaction(privateA, common.HexToAddress("0x02"), params.OrdinaryTx, common.Big1, big.NewInt(5), uint64(now+100), 1000000)
+	txB := makeTransaction(privateB, common.HexToAddress("0x03"), params.OrdinaryTx, common.Big1, big.NewInt(3), uint64(now+100), 1000000)
+	txC := makeTransaction(privateC, common.HexToAddress("0x04"), params.OrdinaryTx, common.Big1, big.NewInt(3), uint64(now+50), 1000000)
+	deletedTx := makeTransaction(privateC, common.HexToAddress("0x05"), params.OrdinaryTx, common.Big1, big.NewInt(10), uint64(now+100), 1000000)
+	expiredTx := makeTransaction(privateB, common.HexToAddress("0x06"), params.OrdinaryTx, common.Big1, big.NewInt(10), uint64(now-100), 1000000)
+
+	queue := NewTxQueue()
+	queue.PushBatch(types.Transactions{txA1, txA2, txB, txC, deletedTx, expiredTx})
+	queue.Del(deletedTx.Hash())
+
+	// every sender has one transaction in a round. The transaction which will expire earlier is in front if their gas price are same
+	result := queue.PopByPrice(uint32(now), 10)
+	assert.Equal(t, []*types.Transaction{txA2, txC, txB, txA1}, result)
+	assert.Equal(t, 6, len(queue.TxsQueue))
+
+	result = queue.PopByPrice(uint32(now), 2)
+	assert.Equal(t, []*types.Transaction{txA2, txC}, result)
+	result = queue.PopByPrice(uint32(now), 0)
+	assert.Equal(t, 0, len(result))
+
+	// box transaction is timeout if any sub transaction is timeout
+	queue = NewTxQueue()
+	data, err := types.MarshalBoxData(types.Transactions{makeTx(common.HexToAddress("0x01"), now+100), makeTx(common.HexToAddress("0x02"), now-100)})
+	assert.NoError(t, err)
+	boxTx := makeBoxTransaction(common.HexToAddress("0x10"), data, uint64(now+100))
+	queue.PushBatch(types.Transactions{boxTx, txB})
+	result = queue.PopByPrice(uint32(now), 10)
+	assert.Equal(t, []*types.Transaction{txB}, result)
+}
+
+func TestTxQueue_Cheapest(t *testing.T) {
+	now := time.Now().Unix()
+	privateA, _ := crypto.GenerateKey()
+	privateB, _ := crypto.GenerateKey()
+	txA1 := makeTransaction(privateA, common.HexToAddress("0x01"), params.OrdinaryTx, common.Big1, big.NewInt(4), uint64(now+100), 1000000)
+	txA2 := makeTransaction(privateA, common.HexToAddress("0x02"), params.OrdinaryTx, common.Big1, big.NewInt(3), uint64(now+100), 1000000)
+	txB1 := makeTransaction(privateB, common.HexToAddress("0x03"), params.OrdinaryTx, common.Big1, big.NewInt(2), uint64(now+100), 1000000)
+	txB2 := makeTransaction(privateB, common.HexToAddress("0x04"), params.OrdinaryTx, common.Big1, big.NewInt(2), uint64(now+100), 1000000)
+
+	queue := NewTxQueue()
+	assert.Nil(t, queue.Cheapest(nil))
+	queue.PushBatch(types.Transactions{txA1, txA2, txB1, txB2})
+	assert.Equal(t, 4, queue.Len())
+	assert.Equal(t, 2, queue.SenderLen(txA1.From()))
+
+	// the older one is cheapest if their gas price are same
+	assert.Equal(t, txB1, queue.Cheapest(nil))
+	fromA := txA1.From()
+	assert.Equal(t, txA2, queue.Cheapest(&fromA))
+
+	// deleted transactions are ignored
+	queue.Del(txB1.Hash())
+	queue.Del(txB1.Hash())
+	assert.Equal(t, 3, queue.Len())
+	assert.Equal(t, 1, queue.SenderLen(txB1.From()))
+	assert.Equal(t, txB2, queue.Cheapest(nil))
+
+	// the deleted transaction is pushed back and keeps its receiving order
+	queue.Push(txB1)
+	assert.Equal(t, 4, queue.Len())
+	assert.Equal(t, txB1, queue.Cheapest(nil))
+	assert.Equal(t, txB1, queue.Get(txB1.Hash()))
+
+	// timeout transactions are removed by pop
+	expiredTx := makeTransaction(privateA, common.HexToAddress("0x05"), params.OrdinaryTx, common.Big1, big.NewInt(1), uint64(now-100), 1000000)
+	queue.Push(expiredTx)
+	assert.Equal(t, 5, queue.Len())
+	queue.Pop(uint32(now), 10)
+	assert.Equal(t, 4, queue.Len())
+	assert.Equal(t, 2, queue.SenderLen(fromA))
+	assert.Equal(t, txB1, queue.Cheapest(nil))
+}
+
+func TestTxQueue_Compact(t *testing.T) {
+	now := time.Now().Unix()
+	queue := NewTxQueue()
+	tx1 := makeTx(common.HexToAddress("0x01"), now+100)
+	tx2 := makeTx(common.HexToAddress("0x02"), now+100)
+	tx3 := makeTx(common.HexToAddress("0x03"), now-100)
+	queue.PushBatch(types.Transactions{tx1, tx2, tx3})
+	queue.Del(tx1.Hash())
+	assert.Equal(t, 3, len(queue.TxsQueue))
+	assert.Equal(t, 2, queue.Len())
+	assert.Nil(t, queue.Get(tx1.Hash()))
+
+	// nothing is removed if there are fewer deleted transactions than live ones
+	assert.Empty(t, queue.Compact(uint32(now-200)))
+	assert.Equal(t, 3, len(queue.TxsQueue))
+
+	// deleted and timeout transactions are removed
+	expired := queue.Compact(uint32(now))
+	assert.Equal(t, []common.Hash{tx3.Hash()}, expired)
+	assert.Equal(t, types.Transactions{tx2}, types.Transactions(queue.TxsQueue))
+	assert.Equal(t, 1, len(queue.TxsStatus))
+	assert.Equal(t, 1, queue.Len())
+
+	// remove
+	queue.Remove(tx2.Hash())
+	assert.Empty(t, queue.TxsQueue)
+	assert.Empty(t, queue.TxsStatus)
+	assert.Equal(t, 0, queue.Len())
+}
@@ -75,7 +75,7 @@ func makeTx(to common.Address, expiration int64) *types.Transaction {
 		to,
 		params.OrdinaryTx,
 		new(big.Int).SetInt64(100),
-		common.Big1,
+		params.MinGasPrice,
 		uint64(expiration),
 		1000000)
 }
@@ -85,7 +85,7 @@ func makeExpirationTx(to common.Address) *types.Transaction {
 }
 
 func makeBoxTransaction(from common.Address, data []byte, expiration uint64) *types.Transaction {
-	return types.NoReceiverTransaction(from, new(big.Int).SetInt64(10000), 20000, new(big.Int).SetInt64(30000), data, params.BoxTx, chainID, expiration, "", "")
+	return types.NoReceiverTransaction(from, new(big.Int).SetInt64(10000), 20000, params.MinGasPrice, data, params.BoxTx, chainID, expiration, "", "")
 }
 
 func makeTransaction(fromPrivate *ecdsa.PrivateKey, to common.Address, txType uint16, amount, gasPrice *big.Int, expiration uint64, gasLimit uint64) *types.Transaction {
@@ -125,6 +125,9 @@ type signAccountMarshaling struct {
 }
 type Signers []SignAccount
 
+// SignerWeightThreshold is the total weight of signers required by the transaction of multisig account
+const SignerWeightThreshold = 100
+
 func (signers Signers) Len() int {
 	return len(signers)
 }
@@ -147,6 +150,18 @@ func (signers Signers) ToSignerMap() SignerMap {
 	return m
 }
 
+// Weight 计算签名者在多签账户中的权重总和
+func (signers Signers) Weight(addresses []common.Address) int64 {
+	signersMap := signers.ToSignerMap()
+	var totalWeight int64 = 0
+	for _, addr := range addresses {
+		if w, ok := signersMap[addr]; ok {
+			totalWeight = totalWeight + int64(w)
+		}
+	}
+	return totalWeight
+}
+
 func (signers Signers) String() string {
 	if len(signers) > 0 {
 		records := make([]string, 0, len(signers))
@@ -412,12 +427,14 @@ type AccountAccessor interface {
 	SetAssetCodeTotalSupply(code common.Hash, val *big.Int) error
 	GetAssetCodeState(code common.Hash, key string) (string, error)
 	SetAssetCodeState(code common.Hash, key string, val string) error
+	GetAssetCodes() ([]*Asset, error)
 
 	GetAssetIdState(id common.Hash) (string, error)
 	SetAssetIdState(id common.Hash, data string) error
 
 	GetEquityState(id common.Hash) (*AssetEquity, error)
 	SetEquityState(id common.Hash, equity *AssetEquity) error
+	GetEquities() ([]*AssetEquity, error)
 
 	SetSingers(signers Signers) error
 	GetSigners() Signers
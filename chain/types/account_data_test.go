@@ -85,3 +85,10 @@ func TestAccountData_MarshalJSON_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, account, decode)
 }
+
+func TestSigners_Weight(t *testing.T) {
+	signers := Signers{{Address: common.HexToAddress("0x1"), Weight: 50}, {Address: common.HexToAddress("0x2"), Weight: 60}}
+	assert.Equal(t, int64(0), signers.Weight(nil))
+	assert.Equal(t, int64(50), signers.Weight([]common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x3")}))
+	assert.Equal(t, int64(110), signers.Weight([]common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}))
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+)
+
+const (
+	// BloomByteLength represents the number of bytes used in a bloom filter.
+	BloomByteLength = 256
+	// BloomBitLength represents the number of bits used in a bloom filter.
+	BloomBitLength = 8 * BloomByteLength
+)
+
+// Bloom represents a 2048 bit bloom filter. It is used to find out the blocks which may contain the events we want
+type Bloom [BloomByteLength]byte
+
+// BytesToBloom converts a byte slice to a bloom filter.
+func BytesToBloom(b []byte) Bloom {
+	var bloom Bloom
+	bloom.SetBytes(b)
+	return bloom
+}
+
+// SetBytes sets the content of b to the given bytes. If b is larger than len(bytes), it will be cropped from the left.
+func (b *Bloom) SetBytes(d []byte) {
+	if len(d) > BloomByteLength {
+		d = d[len(d)-BloomByteLength:]
+	}
+	copy(b[BloomByteLength-len(d):], d)
+}
+
+// Add adds d to the filter.
+func (b *Bloom) Add(d []byte) {
+	for _, pos := range bloomBits(d) {
+		b[BloomByteLength-1-pos/8] |= byte(1) << (pos % 8)
+	}
+}
+
+// Or merges another bloom filter into b
+func (b *Bloom) Or(other Bloom) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+// Test checks if d may be in the filter. It may be wrong when returns true, but it must be right when returns false
+func (b Bloom) Test(d []byte) bool {
+	for _, pos := range bloomBits(d) {
+		if b[BloomByteLength-1-pos/8]&(byte(1)<<(pos%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty returns true if nothing has been added into the filter
+func (b Bloom) IsEmpty() bool {
+	return b == Bloom{}
+}
+
+// Bytes returns the backing byte slice of the bloom
+func (b Bloom) Bytes() []byte {
+	return b[:]
+}
+
+// MarshalText encodes b as a hex string with 0x prefix.
+func (b Bloom) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+// UnmarshalText b as a hex string with 0x prefix.
+func (b *Bloom) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("Bloom", input, b[:], true)
+}
+
+// MatchFilter checks if the events in bloom may match the filter conditions.
+// The event should be sent by one of addresses, and each topic should be one of topics[i]. Empty conditions match all
+func (b Bloom) MatchFilter(addresses []common.Address, topics [][]common.Hash) bool {
+	if b.IsEmpty() {
+		return false
+	}
+	if len(addresses) > 0 {
+		included := false
+		for _, addr := range addresses {
+			if b.Test(addr.Bytes()) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, sub := range topics {
+		if len(sub) == 0 {
+			continue // wildcard
+		}
+		included := false
+		for _, topic := range sub {
+			if b.Test(topic.Bytes()) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
+
+// EventsBloom creates a bloom filter which contains the addresses and topics of events
+func EventsBloom(events []*Event) Bloom {
+	var bloom Bloom
+	for _, event := range events {
+		bloom.Add(event.Address.Bytes())
+		for _, topic := range event.Topics {
+			bloom.Add(topic.Bytes())
+		}
+	}
+	return bloom
+}
+
+// bloomBits picks 3 bits positions by the hash of data
+func bloomBits(d []byte) [3]uint {
+	var result [3]uint
+	h := crypto.Keccak256(d)
+	for i := 0; i < len(result); i++ {
+		// take the low 11 bits of each of the first three pairs of bytes
+		result[i] = (uint(h[2*i])<<8 | uint(h[2*i+1])) & (BloomBitLength - 1)
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBloom_Add_Test(t *testing.T) {
+	var bloom Bloom
+	assert.True(t, bloom.IsEmpty())
+	assert.False(t, bloom.Test([]byte("lemo")))
+
+	bloom.Add([]byte("lemo"))
+	assert.False(t, bloom.IsEmpty())
+	assert.True(t, bloom.Test([]byte("lemo")))
+	assert.False(t, bloom.Test([]byte("chain")))
+
+	// bytes
+	assert.Equal(t, bloom, BytesToBloom(bloom.Bytes()))
+	assert.Equal(t, Bloom{}, BytesToBloom(nil))
+
+	// or
+	var other Bloom
+	other.Add([]byte("chain"))
+	bloom.Or(other)
+	assert.True(t, bloom.Test([]byte("lemo")))
+	assert.True(t, bloom.Test([]byte("chain")))
+}
+
+func TestBloom_MarshalText_UnmarshalText(t *testing.T) {
+	var bloom Bloom
+	bloom.Add([]byte("lemo"))
+	text, err := bloom.MarshalText()
+	assert.NoError(t, err)
+
+	var decoded Bloom
+	err = decoded.UnmarshalText(text)
+	assert.NoError(t, err)
+	assert.Equal(t, bloom, decoded)
+	assert.Error(t, decoded.UnmarshalText([]byte("0x1234")))
+}
+
+func TestBloom_MatchFilter(t *testing.T) {
+	addr1 := common.HexToAddress("0x10000")
+	addr2 := common.HexToAddress("0x20000")
+	topic1 := common.HexToHash("0x11")
+	topic2 := common.HexToHash("0x22")
+	bloom := EventsBloom([]*Event{{Address: addr1, Topics: []common.Hash{topic1}}})
+
+	assert.False(t, Bloom{}.MatchFilter(nil, nil))
+	assert.True(t, bloom.MatchFilter(nil, nil))
+	assert.True(t, bloom.MatchFilter([]common.Address{addr1}, nil))
+	assert.True(t, bloom.MatchFilter([]common.Address{addr2, addr1}, nil))
+	assert.False(t, bloom.MatchFilter([]common.Address{addr2}, nil))
+	assert.True(t, bloom.MatchFilter(nil, [][]common.Hash{{topic1}}))
+	assert.True(t, bloom.MatchFilter(nil, [][]common.Hash{nil, {topic1}}))
+	assert.True(t, bloom.MatchFilter([]common.Address{addr1}, [][]common.Hash{{topic2, topic1}}))
+	assert.False(t, bloom.MatchFilter([]common.Address{addr1}, [][]common.Hash{{topic2}}))
+}
@@ -279,11 +279,20 @@ func (c ChangeLogSlice) FindByType(target *ChangeLog) *ChangeLog {
 
 // MerkleRootSha compute the root hash of ChangeLog merkle trie
 func (c ChangeLogSlice) MerkleRootSha() common.Hash {
+	return c.merkleTree().Root()
+}
+
+// MerkleProof returns the proof of the ChangeLog at index. It can be verified with the LogRoot in header by merkle.Verify
+func (c ChangeLogSlice) MerkleProof(index int) ([]merkle.MerkleNode, error) {
+	return c.merkleTree().Proof(index)
+}
+
+func (c ChangeLogSlice) merkleTree() *merkle.MerkleTree {
 	leaves := make([]common.Hash, len(c))
 	for i, item := range c {
 		leaves[i] = item.Hash()
 	}
-	return merkle.New(leaves).Root()
+	return merkle.New(leaves)
 }
 
 // Undo reverts the change. Its behavior depends on ChangeLog.ChangeLogType
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/merkle"
 	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
 	"github.com/stretchr/testify/assert"
 	"math/big"
@@ -102,6 +103,14 @@ func (f *testAccount) SetEquityState(id common.Hash, equity *AssetEquity) error
 	panic("implement me")
 }
 
+func (f *testAccount) GetAssetCodes() ([]*Asset, error) {
+	panic("implement me")
+}
+
+func (f *testAccount) GetEquities() ([]*AssetEquity, error) {
+	panic("implement me")
+}
+
 func (f *testAccount) GetTxCount() uint32 { return f.GetTxCount() }
 
 func (f *testAccount) SetTxCount(count uint32) {
@@ -299,6 +308,21 @@ func TestChangeLog_Hash(t *testing.T) {
 	}
 }
 
+func TestChangeLogSlice_MerkleProof(t *testing.T) {
+	logs := make(ChangeLogSlice, 0)
+	for _, test := range getCustomTypeData() {
+		logs = append(logs, test.input)
+	}
+	root := logs.MerkleRootSha()
+	for i, cl := range logs {
+		proof, err := logs.MerkleProof(i)
+		assert.NoError(t, err)
+		assert.True(t, merkle.Verify(cl.Hash(), root, proof))
+	}
+	_, err := ChangeLogSlice{}.MerkleProof(0)
+	assert.Equal(t, merkle.ErrIndexOutOfRange, err)
+}
+
 func TestChangeLog_EncodeRLP_DecodeRLP(t *testing.T) {
 	tests := getCustomTypeData()
 	for i, test := range tests {
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
+	"io"
+	"math/big"
+)
+
+//go:generate gencodec -type Receipt -field-override receiptMarshaling -out gen_receipt_json.go
+//go:generate gencodec -type InternalTx -field-override internalTxMarshaling -out gen_internal_tx_json.go
+
+const (
+	// ReceiptStatusFailed is the status code of a transaction if the vm execution failed
+	ReceiptStatusFailed = uint32(0)
+	// ReceiptStatusSuccessful is the status code of a transaction if the vm execution succeeded
+	ReceiptStatusSuccessful = uint32(1)
+)
+
+// Receipt represents the execution result of a transaction
+type Receipt struct {
+	TxHash common.Hash `json:"transactionHash" gencodec:"required"`
+	// Status is ReceiptStatusFailed if the vm returned an error
+	Status uint32 `json:"status" gencodec:"required"`
+	// VmErr is the error message from vm. It is empty if the execution is successful
+	VmErr string `json:"vmErr"`
+	// GasUsed is the gas used by this transaction only. The gas of sub transactions in a box transaction is included
+	GasUsed uint64 `json:"gasUsed" gencodec:"required"`
+	// CumulativeGasUsed is the total gas used in the block when this transaction is finished
+	CumulativeGasUsed uint64   `json:"cumulativeGasUsed" gencodec:"required"`
+	Events            []*Event `json:"events" gencodec:"required"`
+	// ContractAddress is only set by the transaction which creates a contract successfully
+	ContractAddress common.Address `json:"contractAddress"`
+	// InternalTxs are the value transfers made by contracts. The transfers in reverted calls are not included
+	InternalTxs []*InternalTx `json:"internalTxs"`
+
+	// These fields are filled in when the receipt is saved with its block
+	BlockHash common.Hash `json:"blockHash"`
+	Height    uint32      `json:"height"`
+}
+
+type receiptMarshaling struct {
+	Status            hexutil.Uint32
+	GasUsed           hexutil.Uint64
+	CumulativeGasUsed hexutil.Uint64
+	Height            hexutil.Uint32
+}
+
+// rlpStorageReceipt contains all fields of receipt, and the events are also flattened
+type rlpStorageReceipt struct {
+	TxHash            common.Hash
+	Status            uint32
+	VmErr             string
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	Events            []*EventForStorage
+	ContractAddress   common.Address
+	InternalTxs       []*InternalTx
+	BlockHash         common.Hash
+	Height            uint32
+}
+
+// InternalTx is a value transfer made by contract code during the execution of transaction
+type InternalTx struct {
+	From  common.Address `json:"from" gencodec:"required"`
+	To    common.Address `json:"to" gencodec:"required"`
+	Value *big.Int       `json:"value" gencodec:"required"`
+	// Depth is the depth of the call which makes the transfer. The first call from transaction is 0
+	Depth uint32 `json:"depth" gencodec:"required"`
+}
+
+type internalTxMarshaling struct {
+	Value *hexutil.Big10
+	Depth hexutil.Uint32
+}
+
+// NewReceipt creates a receipt by the execution result of transaction
+func NewReceipt(txHash common.Hash, vmErr error, gasUsed, cumulativeGasUsed uint64) *Receipt {
+	r := &Receipt{
+		TxHash:            txHash,
+		Status:            ReceiptStatusSuccessful,
+		GasUsed:           gasUsed,
+		CumulativeGasUsed: cumulativeGasUsed,
+		Events:            make([]*Event, 0),
+		InternalTxs:       make([]*InternalTx, 0),
+	}
+	if vmErr != nil {
+		r.Status = ReceiptStatusFailed
+		r.VmErr = vmErr.Error()
+	}
+	return r
+}
+
+// EncodeRLP implements rlp.Encoder. Receipt is not a consensus data, so we store all fields of it
+func (r *Receipt) EncodeRLP(w io.Writer) error {
+	events := make([]*EventForStorage, len(r.Events))
+	for i, event := range r.Events {
+		events[i] = (*EventForStorage)(event)
+	}
+	return rlp.Encode(w, rlpStorageReceipt{
+		TxHash:            r.TxHash,
+		Status:            r.Status,
+		VmErr:             r.VmErr,
+		GasUsed:           r.GasUsed,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Events:            events,
+		ContractAddress:   r.ContractAddress,
+		InternalTxs:       r.InternalTxs,
+		BlockHash:         r.BlockHash,
+		Height:            r.Height,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (r *Receipt) DecodeRLP(s *rlp.Stream) error {
+	var dec rlpStorageReceipt
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	events := make([]*Event, len(dec.Events))
+	for i, event := range dec.Events {
+		events[i] = (*Event)(event)
+	}
+	*r = Receipt{
+		TxHash:            dec.TxHash,
+		Status:            dec.Status,
+		VmErr:             dec.VmErr,
+		GasUsed:           dec.GasUsed,
+		CumulativeGasUsed: dec.CumulativeGasUsed,
+		Events:            events,
+		ContractAddress:   dec.ContractAddress,
+		InternalTxs:       dec.InternalTxs,
+		BlockHash:         dec.BlockHash,
+		Height:            dec.Height,
+	}
+	return nil
+}
+
+func (r *Receipt) String() string {
+	return fmt.Sprintf("receipt: %s status: %d gasUsed: %d cumulativeGasUsed: %d events: %d vmErr: %s", r.TxHash.Hex(), r.Status, r.GasUsed, r.CumulativeGasUsed, len(r.Events), r.VmErr)
+}
+
+type Receipts []*Receipt
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func getReceipt() *Receipt {
+	receipt := NewReceipt(common.HexToHash("0x11"), errors.New("execution reverted"), 21000, 42000)
+	receipt.Events = append(receipt.Events, &Event{
+		Address: common.HexToAddress("0x10000"),
+		Topics:  []common.Hash{TopicRunFail},
+		Data:    []byte{},
+		TxHash:  receipt.TxHash,
+		TxIndex: 1,
+	})
+	receipt.InternalTxs = append(receipt.InternalTxs, &InternalTx{
+		From:  common.HexToAddress("0x10000"),
+		To:    common.HexToAddress("0x20000"),
+		Value: big.NewInt(100),
+		Depth: 1,
+	})
+	receipt.BlockHash = common.HexToHash("0x22")
+	receipt.Height = 10
+	return receipt
+}
+
+func TestNewReceipt(t *testing.T) {
+	receipt := NewReceipt(common.HexToHash("0x11"), nil, 21000, 42000)
+	assert.Equal(t, ReceiptStatusSuccessful, receipt.Status)
+	assert.Equal(t, "", receipt.VmErr)
+	assert.Equal(t, 0, len(receipt.Events))
+	assert.Equal(t, 0, len(receipt.InternalTxs))
+
+	receipt = getReceipt()
+	assert.Equal(t, ReceiptStatusFailed, receipt.Status)
+	assert.Equal(t, "execution reverted", receipt.VmErr)
+}
+
+func TestReceipt_EncodeRLP_DecodeRLP(t *testing.T) {
+	receipt := getReceipt()
+
+	data, err := rlp.EncodeToBytes(receipt)
+	assert.NoError(t, err)
+	decoded := new(Receipt)
+	err = rlp.DecodeBytes(data, decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, receipt, decoded)
+	// the derived fields of event should be stored too
+	assert.Equal(t, receipt.TxHash, decoded.Events[0].TxHash)
+	assert.Equal(t, uint(1), decoded.Events[0].TxIndex)
+}
+
+func TestReceipt_MarshalJSON_UnmarshalJSON(t *testing.T) {
+	receipt := getReceipt()
+
+	data, err := json.Marshal(receipt)
+	assert.NoError(t, err)
+	decoded := new(Receipt)
+	err = json.Unmarshal(data, decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, receipt, decoded)
+
+	// missing required field
+	err = json.Unmarshal([]byte(`{"transactionHash":"0x0000000000000000000000000000000000000000000000000000000000000011"}`), decoded)
+	assert.Error(t, err)
+}
@@ -37,11 +37,20 @@ type Transactions []*Transaction
 
 // MerkleRootSha compute the root hash of transaction merkle trie
 func (ts Transactions) MerkleRootSha() common.Hash {
+	return ts.merkleTree().Root()
+}
+
+// MerkleProof returns the proof of the transaction at index. It can be verified with the TxRoot in header by merkle.Verify
+func (ts Transactions) MerkleProof(index int) ([]merkle.MerkleNode, error) {
+	return ts.merkleTree().Proof(index)
+}
+
+func (ts Transactions) merkleTree() *merkle.MerkleTree {
 	leaves := make([]common.Hash, len(ts))
 	for i, item := range ts {
 		leaves[i] = item.Hash()
 	}
-	return merkle.New(leaves).Root()
+	return merkle.New(leaves)
 }
 
 type Transaction struct {
@@ -371,9 +380,13 @@ func (tx *Transaction) Clone() *Transaction {
 	cpy.hash = atomic.Value{}
 
 	if tx.data.Recipient != nil {
-		*cpy.data.Recipient = *tx.data.Recipient
+		to := *tx.data.Recipient
+		cpy.data.Recipient = &to
+	}
+	if tx.data.GasPayer != nil {
+		gasPayer := *tx.data.GasPayer
+		cpy.data.GasPayer = &gasPayer
 	}
-	*cpy.data.GasPayer = *tx.data.GasPayer
 
 	if tx.data.Sigs != nil {
 		cpy.data.Sigs = make([][]byte, len(tx.data.Sigs), len(tx.data.Sigs))
@@ -388,7 +401,8 @@ func (tx *Transaction) Clone() *Transaction {
 		copy(cpy.data.Data, tx.data.Data)
 	}
 	if tx.data.Hash != nil {
-		*cpy.data.Hash = *tx.data.Hash
+		hash := *tx.data.Hash
+		cpy.data.Hash = &hash
 	}
 	if tx.data.GasPrice != nil {
 		cpy.data.GasPrice = new(big.Int).Set(tx.data.GasPrice)
@@ -399,6 +413,17 @@ func (tx *Transaction) Clone() *Transaction {
 	return &cpy
 }
 
+// WithSigs returns a copy of transaction with the signatures replaced
+func (tx *Transaction) WithSigs(sigs [][]byte) *Transaction {
+	cpy := tx.Clone()
+	cpy.data.Sigs = nil
+	if sigs != nil {
+		cpy.data.Sigs = make([][]byte, len(sigs), len(sigs))
+		copy(cpy.data.Sigs, sigs)
+	}
+	return cpy
+}
+
 // VerifyTxBody isBlockTx 为true表示验证block中的tx, 为false表示验证收到的交易
 func (tx *Transaction) VerifyTxBody(chainID uint16, timeStamp uint64, isBlockTx bool) (err error) {
 	defer func() {
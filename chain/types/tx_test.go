@@ -5,6 +5,7 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/merkle"
 	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
 	"github.com/stretchr/testify/assert"
 	"math/big"
@@ -283,6 +284,18 @@ func TestTransaction_MarshalJSON_UnmarshalJSON_bigTx(t *testing.T) {
 	assert.Equal(t, txV.Hash(), parsedTx.Hash())
 }
 
+func TestTransactions_MerkleProof(t *testing.T) {
+	txs := Transactions{testTx, testTxBig, testTx}
+	root := txs.MerkleRootSha()
+	for i, tx := range txs {
+		proof, err := txs.MerkleProof(i)
+		assert.NoError(t, err)
+		assert.True(t, merkle.Verify(tx.Hash(), root, proof))
+	}
+	_, err := txs.MerkleProof(3)
+	assert.Equal(t, merkle.ErrIndexOutOfRange, err)
+}
+
 func TestTransaction_Cost(t *testing.T) {
 	assert.Equal(t, big.NewInt(201), testTx.Cost())
 }
@@ -305,6 +318,25 @@ func TestGasPgnatureTx(t *testing.T) {
 	}
 }
 
+func TestTransaction_WithSigs(t *testing.T) {
+	sigTx, err := MakeSigner().SignTx(testTx, testPrivate)
+	assert.NoError(t, err)
+	hash := sigTx.Hash()
+
+	// remove signatures
+	unsigned := sigTx.WithSigs(nil)
+	assert.Empty(t, unsigned.Sigs())
+	assert.Equal(t, testTx.Hash(), unsigned.Hash())
+	assert.Equal(t, hash, sigTx.Hash())
+
+	// replace signatures
+	sigs := [][]byte{sigTx.Sigs()[0], sigTx.Sigs()[0]}
+	doubleSigTx := unsigned.WithSigs(sigs)
+	sigs[1] = nil
+	assert.Equal(t, [][]byte{sigTx.Sigs()[0], sigTx.Sigs()[0]}, doubleSigTx.Sigs())
+	assert.NotEqual(t, hash, doubleSigTx.Hash())
+}
+
 func TestTransaction_txlen(t *testing.T) {
 	sigTx, err := MakeSigner().SignTx(testTx, testPrivate)
 	assert.NoError(t, err)
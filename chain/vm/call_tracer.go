@@ -0,0 +1,116 @@
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+)
+
+// FrameTracer is a Tracer which is also notified when a call frame starts and ends, including the internal calls made by contracts
+type FrameTracer interface {
+	Tracer
+	CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
+	CaptureExit(output []byte, gasUsed uint64, err error)
+}
+
+//go:generate gencodec -type CallFrame -field-override callFrameMarshaling -out gen_callframe_json.go
+
+// CallFrame is a call made during the transaction execution. The Value is nil in DELEGATECALL and STATICCALL
+type CallFrame struct {
+	Type    string         `json:"type" gencodec:"required"`
+	From    common.Address `json:"from" gencodec:"required"`
+	To      common.Address `json:"to" gencodec:"required"`
+	Value   *big.Int       `json:"value"`
+	Gas     uint64         `json:"gas" gencodec:"required"`
+	GasUsed uint64         `json:"gasUsed" gencodec:"required"`
+	Input   []byte         `json:"input" gencodec:"required"`
+	Output  []byte         `json:"output"`
+	Error   string         `json:"error,omitempty"`
+	Calls   CallFrames     `json:"calls,omitempty"`
+}
+
+// CallFrames are the sub calls of a frame
+type CallFrames []*CallFrame
+
+type callFrameMarshaling struct {
+	Value   *hexutil.Big10
+	Gas     hexutil.Uint64
+	GasUsed hexutil.Uint64
+	Input   hexutil.Bytes
+	Output  hexutil.Bytes
+}
+
+// CallTracer is a FrameTracer which records the tree of call frames
+type CallTracer struct {
+	root  *CallFrame
+	stack []*CallFrame // the frames which are not finished
+}
+
+// NewCallTracer returns a new call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// CaptureStart implements the Tracer interface. The first frame is captured by CaptureEnter
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the Tracer interface
+func (t *CallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface
+func (t *CallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface. The first frame is finished by CaptureExit
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, duration time.Duration, err error) error {
+	return nil
+}
+
+// CaptureEnter pushes a new frame into the tree
+func (t *CallTracer) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	frame := &CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    to,
+		Gas:   gas,
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = new(big.Int).Set(value)
+	}
+
+	if len(t.stack) == 0 {
+		t.root = frame
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+// CaptureExit fills the result of the latest unfinished frame
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = gasUsed
+	frame.Output = common.CopyBytes(output)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+// Result returns the first call frame. It is nil if there is no vm call
+func (t *CallTracer) Result() *CallFrame {
+	return t.root
+}
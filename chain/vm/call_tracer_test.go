@@ -0,0 +1,107 @@
+package vm
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/chain/account"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// callContractCode returns the code which calls the address with value, then runs the tail code
+func callContractCode(addr common.Address, value byte, tail []byte) []byte {
+	code := []byte{
+		byte(PUSH1), 0, // retSize
+		byte(PUSH1), 0, // retOffset
+		byte(PUSH1), 0, // inSize
+		byte(PUSH1), 0, // inOffset
+		byte(PUSH1), value,
+		byte(PUSH20),
+	}
+	code = append(code, addr.Bytes()...)
+	code = append(code, byte(GAS), byte(CALL), byte(POP))
+	return append(code, tail...)
+}
+
+func newTracerTestEVM(am *account.Manager, tracer Tracer) *EVM {
+	ctx := Context{
+		CanTransfer: func(am AccountManager, addr common.Address, amount *big.Int) bool {
+			return am.GetAccount(addr).GetBalance().Cmp(amount) >= 0
+		},
+		Transfer: func(am AccountManager, sender, recipient common.Address, amount *big.Int) {
+			senderAccount := am.GetAccount(sender)
+			recipientAccount := am.GetAccount(recipient)
+			senderAccount.SetBalance(new(big.Int).Sub(senderAccount.GetBalance(), amount))
+			recipientAccount.SetBalance(new(big.Int).Add(recipientAccount.GetBalance(), amount))
+		},
+	}
+	return NewEVM(ctx, am, Config{Debug: tracer != nil, Tracer: tracer})
+}
+
+func TestCallTracer(t *testing.T) {
+	ClearData()
+	db := newDB()
+	defer db.Close()
+	am := account.NewManager(common.Hash{}, db)
+
+	sender := am.GetAccount(common.HexToAddress("0x10000"))
+	contractAddr := common.HexToAddress("0x20000")
+	revertContractAddr := common.HexToAddress("0x30000")
+	receiverAddr := common.HexToAddress("0x40000")
+	contract := am.GetAccount(contractAddr)
+	contract.SetBalance(big.NewInt(100))
+	contract.SetCode(callContractCode(receiverAddr, 1, []byte{byte(STOP)}))
+	revertContract := am.GetAccount(revertContractAddr)
+	revertContract.SetBalance(big.NewInt(100))
+	revertContract.SetCode(callContractCode(receiverAddr, 2, []byte{byte(PUSH1), 0, byte(PUSH1), 0, byte(REVERT)}))
+
+	// internal call
+	tracer := NewCallTracer()
+	evm := newTracerTestEVM(am, tracer)
+	_, _, err := evm.Call(sender, contractAddr, []byte{0x12}, 100000, big.NewInt(0))
+	assert.NoError(t, err)
+	root := tracer.Result()
+	assert.Equal(t, "CALL", root.Type)
+	assert.Equal(t, sender.GetAddress(), root.From)
+	assert.Equal(t, contractAddr, root.To)
+	assert.Equal(t, []byte{0x12}, root.Input)
+	assert.Equal(t, uint64(100000), root.Gas)
+	assert.True(t, root.GasUsed > 0)
+	assert.Empty(t, root.Error)
+	assert.Len(t, root.Calls, 1)
+	assert.Equal(t, "CALL", root.Calls[0].Type)
+	assert.Equal(t, contractAddr, root.Calls[0].From)
+	assert.Equal(t, receiverAddr, root.Calls[0].To)
+	assert.Equal(t, big.NewInt(1), root.Calls[0].Value)
+	assert.Equal(t, []*types.InternalTx{{From: contractAddr, To: receiverAddr, Value: big.NewInt(1), Depth: 1}}, evm.InternalTxs())
+	assert.Equal(t, big.NewInt(1), am.GetAccount(receiverAddr).GetBalance())
+
+	// the internal transfer is reverted
+	tracer = NewCallTracer()
+	evm = newTracerTestEVM(am, tracer)
+	_, _, err = evm.Call(sender, revertContractAddr, nil, 100000, big.NewInt(0))
+	assert.Equal(t, errExecutionReverted, err)
+	root = tracer.Result()
+	assert.Equal(t, errExecutionReverted.Error(), root.Error)
+	assert.Len(t, root.Calls, 1)
+	assert.Empty(t, root.Calls[0].Error)
+	assert.Empty(t, evm.InternalTxs())
+	assert.Equal(t, big.NewInt(1), am.GetAccount(receiverAddr).GetBalance())
+
+	// internal transfers are recorded without tracer
+	evm = newTracerTestEVM(am, nil)
+	_, _, err = evm.Call(sender, contractAddr, nil, 100000, big.NewInt(0))
+	assert.NoError(t, err)
+	assert.Len(t, evm.InternalTxs(), 1)
+
+	// contract creation
+	tracer = NewCallTracer()
+	evm = newTracerTestEVM(am, tracer)
+	_, createdAddr, _, err := evm.Create(sender, []byte{byte(STOP)}, 100000, big.NewInt(0))
+	assert.NoError(t, err)
+	root = tracer.Result()
+	assert.Equal(t, "CREATE", root.Type)
+	assert.Equal(t, createdAddr, root.To)
+	assert.Empty(t, root.Calls)
+}
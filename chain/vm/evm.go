@@ -93,6 +93,8 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// internalTxs records the value transfers made by contracts
+	internalTxs []*types.InternalTx
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -108,6 +110,35 @@ func NewEVM(ctx Context, am AccountManager, vmConfig Config) *EVM {
 	return evm
 }
 
+// frameTracer returns the tracer which captures every call frame in debug mode. It is nil if the tracer is not a FrameTracer
+func (evm *EVM) frameTracer() FrameTracer {
+	if !evm.vmConfig.Debug {
+		return nil
+	}
+	tracer, _ := evm.vmConfig.Tracer.(FrameTracer)
+	return tracer
+}
+
+// recordInternalTx records the value transfer made by contract. It returns the count of records before,
+// so that the records can be reverted with the state
+func (evm *EVM) recordInternalTx(from, to common.Address, value *big.Int) int {
+	count := len(evm.internalTxs)
+	if evm.depth > 0 && value != nil && value.Sign() > 0 {
+		evm.internalTxs = append(evm.internalTxs, &types.InternalTx{
+			From:  from,
+			To:    to,
+			Value: new(big.Int).Set(value),
+			Depth: uint32(evm.depth),
+		})
+	}
+	return count
+}
+
+// InternalTxs returns the value transfers made by contracts which are not reverted
+func (evm *EVM) InternalTxs() []*types.InternalTx {
+	return evm.internalTxs
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -122,6 +153,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(CALL, caller.GetAddress(), addr, input, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -145,6 +180,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		return nil, gas, nil
 	}
 	evm.Transfer(evm.am, caller.GetAddress(), to.GetAddress(), value)
+	internalTxsCount := evm.recordInternalTx(caller.GetAddress(), to.GetAddress(), value)
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
 	contract := NewContract(caller, to, value, gas)
@@ -168,6 +204,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if err != nil {
 		log.Error("evm error", "error", err)
 		evm.am.RevertToSnapshot(snapshot)
+		evm.internalTxs = evm.internalTxs[:internalTxsCount]
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
@@ -182,6 +219,16 @@ type AssetDb interface {
 
 // TransferAssetTx
 func (evm *EVM) TransferAssetTx(caller ContractRef, addr common.Address, gas uint64, txData []byte, assetDB AssetDb) (ret []byte, leftOverGas uint64, Err, vmErr error) {
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(CALL, caller.GetAddress(), addr, txData, gas, nil)
+		defer func() {
+			err := vmErr
+			if err == nil {
+				err = Err
+			}
+			tracer.CaptureExit(ret, gas-leftOverGas, err)
+		}()
+	}
 	tradingAsset, err := types.GetTradingAsset(txData)
 	if err != nil {
 		log.Errorf("Unmarshal transfer asset data err: %s", err)
@@ -349,6 +396,10 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(CALLCODE, caller.GetAddress(), addr, input, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -393,6 +444,10 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(DELEGATECALL, caller.GetAddress(), addr, input, gas, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -430,6 +485,10 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(STATICCALL, caller.GetAddress(), addr, input, gas, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -472,6 +531,10 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 
 // Create creates a new contract using code as deployment code.
 func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(CREATE, caller.GetAddress(), crypto.CreateContractAddress(caller.GetAddress(), evm.TxHash), code, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	// Depth check execution. Fail if we're trying to execute above the
 	// limit.
@@ -493,6 +556,7 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	snapshot := evm.am.Snapshot()
 
 	evm.Transfer(evm.am, caller.GetAddress(), contractAddr, value)
+	internalTxsCount := evm.recordInternalTx(caller.GetAddress(), contractAddr, value)
 
 	// initialise a new contract and set the code that is to be used by the
 	// EVM. The contract is a scoped environment for this execution context
@@ -530,6 +594,7 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	// when we're in homestead this also counts for code storage gas errors.
 	if maxCodeSizeExceeded || err != nil {
 		evm.am.RevertToSnapshot(snapshot)
+		evm.internalTxs = evm.internalTxs[:internalTxsCount]
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
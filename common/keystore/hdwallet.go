@@ -0,0 +1,184 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LemoCoinType is the coin type of LemoChain in BIP44 derivation path
+	LemoCoinType = 8888
+	// HardenedKeyStart is the index of first hardened child key in BIP32
+	HardenedKeyStart = uint32(0x80000000)
+	// DefaultMnemonicBits is the entropy bits of mnemonic, which makes 12 words
+	DefaultMnemonicBits = 128
+)
+
+var (
+	// DefaultBaseDerivationPath is the BIP44 path of LemoChain accounts. The account number is appended to it
+	DefaultBaseDerivationPath = DerivationPath{HardenedKeyStart + 44, HardenedKeyStart + LemoCoinType, HardenedKeyStart + 0, 0}
+
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrInvalidChildKey       = errors.New("the derived key is invalid, try next index")
+
+	masterKeySeed = []byte("Bitcoin seed")
+)
+
+// DerivationPath is the BIP32 path to derive key from master key, such as m/44'/8888'/0'/0/0
+type DerivationPath []uint32
+
+// ParseDerivationPath parses the path string. The hardened index is ended with "'"
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) < 2 || strings.TrimSpace(components[0]) != "m" {
+		return nil, ErrInvalidDerivationPath
+	}
+	result := make(DerivationPath, 0, len(components)-1)
+	for _, component := range components[1:] {
+		component = strings.TrimSpace(component)
+		var offset uint32
+		if strings.HasSuffix(component, "'") {
+			offset = HardenedKeyStart
+			component = strings.TrimSuffix(component, "'")
+		}
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || index >= uint64(HardenedKeyStart) {
+			return nil, ErrInvalidDerivationPath
+		}
+		result = append(result, uint32(index)+offset)
+	}
+	return result, nil
+}
+
+// String returns the path string, such as m/44'/8888'/0'/0/0
+func (path DerivationPath) String() string {
+	result := "m"
+	for _, index := range path {
+		if index >= HardenedKeyStart {
+			result = fmt.Sprintf("%s/%d'", result, index-HardenedKeyStart)
+		} else {
+			result = fmt.Sprintf("%s/%d", result, index)
+		}
+	}
+	return result
+}
+
+// AccountPath returns the path of the account number under DefaultBaseDerivationPath
+func AccountPath(index uint32) DerivationPath {
+	path := make(DerivationPath, len(DefaultBaseDerivationPath), len(DefaultBaseDerivationPath)+1)
+	copy(path, DefaultBaseDerivationPath)
+	return append(path, index)
+}
+
+// NewMnemonic generates a BIP39 mnemonic. The bits should be a multiple of 32 in [128, 256]
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// DeriveKey derives the private key from BIP39 mnemonic and passphrase by BIP32 path
+func DeriveKey(mnemonic, passphrase string, path DerivationPath) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	key, chainCode, err := newMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range path {
+		if key, chainCode, err = deriveChildKey(key, chainCode, index); err != nil {
+			return nil, err
+		}
+	}
+	return crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32))
+}
+
+// DeriveAddress derives the account address from BIP39 mnemonic and passphrase by BIP32 path
+func DeriveAddress(mnemonic, passphrase string, path DerivationPath) (common.Address, error) {
+	privateKey, err := DeriveKey(mnemonic, passphrase, path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(privateKey.PublicKey), nil
+}
+
+func newMasterKey(seed []byte) (*big.Int, []byte, error) {
+	mac := hmac.New(sha512.New, masterKeySeed)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, nil, ErrInvalidChildKey
+	}
+	return key, sum[32:], nil
+}
+
+// deriveChildKey derives the private child key by BIP32
+func deriveChildKey(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	curve := crypto.S256()
+	data := make([]byte, 0, 37)
+	if index >= HardenedKeyStart {
+		data = append(data, 0)
+		data = append(data, common.LeftPadBytes(key.Bytes(), 32)...)
+	} else {
+		x, y := curve.ScalarBaseMult(common.LeftPadBytes(key.Bytes(), 32))
+		data = append(data, crypto.CompressPubkey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})...)
+	}
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	data = append(data, indexBytes...)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(curve.Params().N) >= 0 {
+		return nil, nil, ErrInvalidChildKey
+	}
+	child := il.Add(il, key)
+	child.Mod(child, curve.Params().N)
+	if child.Sign() == 0 {
+		return nil, nil, ErrInvalidChildKey
+	}
+	return child, sum[32:], nil
+}
+
+// ImportMnemonic derives the key from mnemonic by the path and stores it in the key directory, encrypting it with the password
+func (ks *KeyStore) ImportMnemonic(mnemonic, passphrase string, path DerivationPath, password string) (common.Address, error) {
+	privateKey, err := DeriveKey(mnemonic, passphrase, path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ks.ImportECDSA(privateKey, password)
+}
+
+// DeriveAccounts derives count accounts from the account number start under DefaultBaseDerivationPath, and stores them in the key directory
+func (ks *KeyStore) DeriveAccounts(mnemonic, passphrase string, start, count uint32, password string) ([]common.Address, error) {
+	if uint64(start)+uint64(count) > uint64(HardenedKeyStart) {
+		return nil, ErrInvalidDerivationPath
+	}
+	result := make([]common.Address, 0, count)
+	for i := start; i < start+count; i++ {
+		address, err := ks.ImportMnemonic(mnemonic, passphrase, AccountPath(i), password)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, address)
+	}
+	return result, nil
+}
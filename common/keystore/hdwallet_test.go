@@ -0,0 +1,97 @@
+package keystore
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/8888'/0'/0/5")
+	assert.NoError(t, err)
+	assert.Equal(t, AccountPath(5), path)
+	assert.Equal(t, "m/44'/8888'/0'/0/5", path.String())
+	assert.Equal(t, "m/44'/8888'/0'/0", DefaultBaseDerivationPath.String())
+
+	for _, invalid := range []string{"", "m", "44'/0", "m/a", "m/-1", "m/2147483648", "m/0''", "n/0"} {
+		_, err = ParseDerivationPath(invalid)
+		assert.Equal(t, ErrInvalidDerivationPath, err, invalid)
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(DefaultMnemonicBits)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, len(strings.Fields(mnemonic)))
+	mnemonic, err = NewMnemonic(256)
+	assert.NoError(t, err)
+	assert.Equal(t, 24, len(strings.Fields(mnemonic)))
+	_, err = NewMnemonic(100)
+	assert.Error(t, err)
+}
+
+func TestDeriveChildKey(t *testing.T) {
+	// BIP32 test vector 1
+	key, chainCode, err := newMasterKey(common.FromHex("000102030405060708090a0b0c0d0e0f"))
+	assert.NoError(t, err)
+	assert.Equal(t, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", common.Bytes2Hex(key.Bytes()))
+	assert.Equal(t, "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", common.Bytes2Hex(chainCode))
+	// m/0'
+	key, chainCode, err = deriveChildKey(key, chainCode, HardenedKeyStart)
+	assert.NoError(t, err)
+	assert.Equal(t, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", common.Bytes2Hex(key.Bytes()))
+	// m/0'/1
+	key, chainCode, err = deriveChildKey(key, chainCode, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", common.Bytes2Hex(key.Bytes()))
+}
+
+func TestDeriveKey(t *testing.T) {
+	// the well known key of test mnemonic at m/44'/60'/0'/0/0
+	path, _ := ParseDerivationPath("m/44'/60'/0'/0/0")
+	privateKey, err := DeriveKey(testMnemonic, "", path)
+	assert.NoError(t, err)
+	assert.Equal(t, "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727", common.Bytes2Hex(crypto.FromECDSA(privateKey)))
+
+	// different account number and passphrase make different addresses
+	address0, err := DeriveAddress(testMnemonic, "", AccountPath(0))
+	assert.NoError(t, err)
+	address1, err := DeriveAddress(testMnemonic, "", AccountPath(1))
+	assert.NoError(t, err)
+	address2, err := DeriveAddress(testMnemonic, "passphrase", AccountPath(0))
+	assert.NoError(t, err)
+	assert.NotEqual(t, address0, address1)
+	assert.NotEqual(t, address0, address2)
+	assert.True(t, strings.HasPrefix(address0.String(), "Lemo"))
+
+	// invalid checksum
+	_, err = DeriveKey(strings.Replace(testMnemonic, "about", "abandon", 1), "", AccountPath(0))
+	assert.Equal(t, ErrInvalidMnemonic, err)
+}
+
+func TestKeyStore_DeriveAccounts(t *testing.T) {
+	ks, clear := newTestKeyStore(t)
+	defer clear()
+
+	addresses, err := ks.DeriveAccounts(testMnemonic, "", 0, 2, "password")
+	assert.NoError(t, err)
+	address0, _ := DeriveAddress(testMnemonic, "", AccountPath(0))
+	address1, _ := DeriveAddress(testMnemonic, "", AccountPath(1))
+	assert.Equal(t, []common.Address{address0, address1}, addresses)
+
+	// restore again is ignored
+	address, err := ks.ImportMnemonic(testMnemonic, "", AccountPath(1), "password")
+	assert.NoError(t, err)
+	assert.Equal(t, address1, address)
+	accounts, err := ks.Accounts()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(accounts))
+
+	// too large account number
+	_, err = ks.DeriveAccounts(testMnemonic, "", HardenedKeyStart-1, 2, "password")
+	assert.Equal(t, ErrInvalidDerivationPath, err)
+}
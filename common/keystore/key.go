@@ -0,0 +1,198 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto/randentropy"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyVersion = 3
+
+	// The N and P parameters of Scrypt encryption algorithm. The standard ones use 256MB memory and take approximately 1s CPU time on a modern processor,
+	// the light ones use 4MB memory and take approximately 100ms CPU time
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	LightScryptN    = 1 << 12
+	LightScryptP    = 6
+
+	scryptR     = 8
+	scryptDKLen = 32
+)
+
+var (
+	ErrDecrypt           = errors.New("could not decrypt key with given password")
+	ErrKeyVersion        = errors.New("unsupported key file version")
+	ErrUnsupportedKDF    = errors.New("unsupported key derivation function")
+	ErrUnsupportedCipher = errors.New("unsupported cipher")
+)
+
+// Key is the decrypted private key of an account
+type Key struct {
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// newKeyFromECDSA creates a key from the private key
+func newKeyFromECDSA(privateKey *ecdsa.PrivateKey) *Key {
+	return &Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+}
+
+// encryptedKeyJSON is the format of key file
+type encryptedKeyJSON struct {
+	Address string     `json:"address"`
+	Crypto  cryptoJSON `json:"crypto"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts the key by password with scrypt, and returns the content of key file
+func EncryptKey(key *Key, password string, scryptN, scryptP int) ([]byte, error) {
+	salt := randentropy.GetEntropyCSPRNG(32)
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	encryptKey := derivedKey[:16]
+	keyBytes := common.LeftPadBytes(crypto.FromECDSA(key.PrivateKey), 32)
+
+	iv := randentropy.GetEntropyCSPRNG(aes.BlockSize)
+	cipherText, err := aesCTRXOR(encryptKey, keyBytes, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	scryptParams := map[string]interface{}{
+		"n":     scryptN,
+		"r":     scryptR,
+		"p":     scryptP,
+		"dklen": scryptDKLen,
+		"salt":  hex.EncodeToString(salt),
+	}
+	encryptedKey := encryptedKeyJSON{
+		Address: key.Address.String(),
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams:    scryptParams,
+			MAC:          hex.EncodeToString(mac),
+		},
+		Version: keyVersion,
+	}
+	return json.MarshalIndent(encryptedKey, "", "\t")
+}
+
+// DecryptKey decrypts the content of key file by password
+func DecryptKey(keyJSON []byte, password string) (*Key, error) {
+	var encryptedKey encryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &encryptedKey); err != nil {
+		return nil, err
+	}
+	if encryptedKey.Version != keyVersion {
+		return nil, ErrKeyVersion
+	}
+	keyBytes, err := decryptKeyBytes(&encryptedKey.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	key := newKeyFromECDSA(privateKey)
+	if key.Address.String() != encryptedKey.Address {
+		return nil, fmt.Errorf("key content mismatch: have account %s, want %s", key.Address.String(), encryptedKey.Address)
+	}
+	return key, nil
+}
+
+func decryptKeyBytes(cryptoJson *cryptoJSON, password string) ([]byte, error) {
+	if cryptoJson.Cipher != "aes-128-ctr" {
+		return nil, ErrUnsupportedCipher
+	}
+	if cryptoJson.KDF != "scrypt" {
+		return nil, ErrUnsupportedKDF
+	}
+	mac, err := hex.DecodeString(cryptoJson.MAC)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(cryptoJson.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, ErrDecrypt
+	}
+	cipherText, err := hex.DecodeString(cryptoJson.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(fmt.Sprint(cryptoJson.KDFParams["salt"]))
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(password), salt, ensureInt(cryptoJson.KDFParams["n"]), ensureInt(cryptoJson.KDFParams["r"]), ensureInt(cryptoJson.KDFParams["p"]), ensureInt(cryptoJson.KDFParams["dklen"]))
+	if err != nil {
+		return nil, err
+	}
+	if len(derivedKey) < scryptDKLen {
+		return nil, ErrDecrypt
+	}
+
+	calculatedMAC := crypto.Keccak256(derivedKey[16:32], cipherText)
+	if !bytes.Equal(calculatedMAC, mac) {
+		return nil, ErrDecrypt
+	}
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	stream := cipher.NewCTR(aesBlock, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, nil
+}
+
+// ensureInt converts the number which is unmarshaled from json to int. It returns 0 if x is not a number
+func ensureInt(x interface{}) int {
+	switch v := x.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,28 @@
+package keystore
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEncryptKey(t *testing.T) {
+	privateKey, _ := crypto.GenerateKey()
+	key := newKeyFromECDSA(privateKey)
+	content, err := EncryptKey(key, "password", LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), key.Address.String())
+
+	decrypted, err := DecryptKey(content, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address, decrypted.Address)
+	assert.Equal(t, crypto.FromECDSA(privateKey), crypto.FromECDSA(decrypted.PrivateKey))
+
+	// wrong password
+	_, err = DecryptKey(content, "wrong")
+	assert.Equal(t, ErrDecrypt, err)
+
+	// invalid json
+	_, err = DecryptKey([]byte("{}"), "password")
+	assert.Equal(t, ErrKeyVersion, err)
+}
@@ -0,0 +1,225 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoMatch = errors.New("no key for given address")
+	ErrLocked  = errors.New("account is locked")
+)
+
+type unlocked struct {
+	*Key
+	abort chan struct{}
+}
+
+// KeyStore manages the encrypted key files in a directory
+type KeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+
+	unlocked map[common.Address]*unlocked
+	mu       sync.RWMutex
+}
+
+// NewKeyStore creates a keystore for the directory. The key files are encrypted by scrypt with scryptN and scryptP
+func NewKeyStore(dir string, scryptN, scryptP int) *KeyStore {
+	return &KeyStore{
+		dir:      dir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[common.Address]*unlocked),
+	}
+}
+
+// keyFileName returns the file name of key, such as "UTC--2019-01-01T00-00-00.000000000Z--0000000000000000000000000000000000000000"
+func keyFileName(address common.Address) string {
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z")
+	return fmt.Sprintf("UTC--%s--%x", ts, address[:])
+}
+
+// NewAccount generates a new key and stores it in the key directory, encrypting it with the password
+func (ks *KeyStore) NewAccount(password string) (common.Address, error) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ks.ImportECDSA(privateKey, password)
+}
+
+// ImportECDSA stores the private key in the key directory, encrypting it with the password
+func (ks *KeyStore) ImportECDSA(privateKey *ecdsa.PrivateKey, password string) (common.Address, error) {
+	key := newKeyFromECDSA(privateKey)
+	if _, err := ks.find(key.Address); err == nil {
+		return key.Address, nil
+	}
+	content, err := EncryptKey(key, password, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return common.Address{}, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(ks.dir, keyFileName(key.Address)), content, 0600); err != nil {
+		return common.Address{}, err
+	}
+	log.Infof("New account is stored in keystore. address: %s", key.Address.String())
+	return key.Address, nil
+}
+
+// Accounts returns the addresses of all key files in the key directory
+func (ks *KeyStore) Accounts() ([]common.Address, error) {
+	files, err := ioutil.ReadDir(ks.dir)
+	if os.IsNotExist(err) {
+		return []common.Address{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	// the file names start with creation time
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	result := make([]common.Address, 0, len(files))
+	for _, file := range files {
+		if address, ok := parseKeyFileName(file); ok {
+			result = append(result, address)
+		}
+	}
+	return result, nil
+}
+
+// parseKeyFileName returns the address in the name of key file
+func parseKeyFileName(file os.FileInfo) (common.Address, bool) {
+	name := file.Name()
+	if file.IsDir() || !strings.HasPrefix(name, "UTC--") {
+		return common.Address{}, false
+	}
+	index := strings.LastIndex(name, "--")
+	hexAddress := name[index+2:]
+	if len(hexAddress) != common.AddressLength*2 {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(hexAddress), true
+}
+
+// find returns the path of key file
+func (ks *KeyStore) find(address common.Address) (string, error) {
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, file := range files {
+		if fileAddress, ok := parseKeyFileName(file); ok && fileAddress == address {
+			return filepath.Join(ks.dir, file.Name()), nil
+		}
+	}
+	return "", ErrNoMatch
+}
+
+// getDecryptedKey reads the key file and decrypts it with the password
+func (ks *KeyStore) getDecryptedKey(address common.Address, password string) (*Key, error) {
+	path, err := ks.find(address)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := DecryptKey(content, password)
+	if err != nil {
+		return nil, err
+	}
+	if key.Address != address {
+		return nil, fmt.Errorf("key content mismatch: have account %s, want %s", key.Address.String(), address.String())
+	}
+	return key, nil
+}
+
+// Unlock decrypts the key and keeps it in memory for the duration. The key is kept until Lock or the node is stopped if duration is 0
+func (ks *KeyStore) Unlock(address common.Address, password string, duration time.Duration) error {
+	key, err := ks.getDecryptedKey(address, password)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if old, ok := ks.unlocked[address]; ok {
+		close(old.abort)
+	}
+	u := &unlocked{Key: key, abort: make(chan struct{})}
+	ks.unlocked[address] = u
+	if duration > 0 {
+		go ks.expire(address, u, duration)
+	}
+	return nil
+}
+
+// Lock removes the decrypted key from memory
+func (ks *KeyStore) Lock(address common.Address) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if u, ok := ks.unlocked[address]; ok {
+		close(u.abort)
+		delete(ks.unlocked, address)
+	}
+}
+
+func (ks *KeyStore) expire(address common.Address, u *unlocked, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-u.abort:
+		// unlocked again or locked
+	case <-timer.C:
+		ks.mu.Lock()
+		if ks.unlocked[address] == u {
+			delete(ks.unlocked, address)
+		}
+		ks.mu.Unlock()
+	}
+}
+
+// IsUnlocked returns true if the account is unlocked
+func (ks *KeyStore) IsUnlocked(address common.Address) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	_, ok := ks.unlocked[address]
+	return ok
+}
+
+// SignTx signs the transaction with the key of its sender. The account must be unlocked
+func (ks *KeyStore) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	u, ok := ks.unlocked[tx.From()]
+	if !ok {
+		return nil, ErrLocked
+	}
+	return types.MakeSigner().SignTx(tx, u.PrivateKey)
+}
+
+// SignTxWithPassword signs the transaction with the key of its sender. The key is decrypted with the password
+func (ks *KeyStore) SignTxWithPassword(tx *types.Transaction, password string) (*types.Transaction, error) {
+	key, err := ks.getDecryptedKey(tx.From(), password)
+	if err != nil {
+		return nil, err
+	}
+	return types.MakeSigner().SignTx(tx, key.PrivateKey)
+}
@@ -0,0 +1,93 @@
+package keystore
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestKeyStore(t *testing.T) (*KeyStore, func()) {
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.NoError(t, err)
+	return NewKeyStore(dir, LightScryptN, LightScryptP), func() { os.RemoveAll(dir) }
+}
+
+func newTestTx(from common.Address) *types.Transaction {
+	return types.NewTransaction(from, common.HexToAddress("0x01"), big.NewInt(100), 100000, big.NewInt(1000000000), nil, params.OrdinaryTx, 100, uint64(time.Now().Unix()+300), "", "")
+}
+
+func TestKeyStore_NewAccount(t *testing.T) {
+	ks, clear := newTestKeyStore(t)
+	defer clear()
+
+	accounts, err := ks.Accounts()
+	assert.NoError(t, err)
+	assert.Empty(t, accounts)
+
+	address1, err := ks.NewAccount("password1")
+	assert.NoError(t, err)
+	address2, err := ks.NewAccount("password2")
+	assert.NoError(t, err)
+	accounts, err = ks.Accounts()
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{address1, address2}, accounts)
+
+	// import exist key
+	privateKey, _ := crypto.GenerateKey()
+	address3, err := ks.ImportECDSA(privateKey, "password3")
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey), address3)
+	_, err = ks.ImportECDSA(privateKey, "password3")
+	assert.NoError(t, err)
+	accounts, _ = ks.Accounts()
+	assert.Len(t, accounts, 3)
+}
+
+func TestKeyStore_SignTx(t *testing.T) {
+	ks, clear := newTestKeyStore(t)
+	defer clear()
+	address, err := ks.NewAccount("password")
+	assert.NoError(t, err)
+	tx := newTestTx(address)
+
+	// locked
+	_, err = ks.SignTx(tx)
+	assert.Equal(t, ErrLocked, err)
+	assert.Equal(t, ErrDecrypt, ks.Unlock(address, "wrong", 0))
+	assert.Equal(t, ErrNoMatch, ks.Unlock(common.HexToAddress("0x02"), "password", 0))
+
+	// unlocked
+	assert.NoError(t, ks.Unlock(address, "password", 0))
+	assert.True(t, ks.IsUnlocked(address))
+	signedTx, err := ks.SignTx(tx)
+	assert.NoError(t, err)
+	signers, err := types.MakeSigner().GetSigners(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{address}, signers)
+
+	// lock again
+	ks.Lock(address)
+	_, err = ks.SignTx(tx)
+	assert.Equal(t, ErrLocked, err)
+
+	// unlock for a while
+	assert.NoError(t, ks.Unlock(address, "password", 100*time.Millisecond))
+	assert.True(t, ks.IsUnlocked(address))
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, ks.IsUnlocked(address))
+
+	// sign with password
+	signedTx, err = ks.SignTxWithPassword(tx, "password")
+	assert.NoError(t, err)
+	signers, _ = types.MakeSigner().GetSigners(signedTx)
+	assert.Equal(t, []common.Address{address}, signers)
+	_, err = ks.SignTxWithPassword(tx, "wrong")
+	assert.Equal(t, ErrDecrypt, err)
+}
@@ -22,12 +22,14 @@ const (
 var (
 	// There is not a root hash of empty merkle trie, so we use the hash of nil to represent it
 	EmptyTrieHash = common.Sha3Nil
+
+	ErrIndexOutOfRange = errors.New("leaf index out of range")
 )
 
 // MerkleNode 用在获取与验证伴随节点
 type MerkleNode struct {
-	Hash     common.Hash
-	NodeType NodeTypeFlag
+	Hash     common.Hash  `json:"hash"`
+	NodeType NodeTypeFlag `json:"nodeType"`
 }
 
 type MerkleTree struct {
@@ -73,6 +75,24 @@ func (m *MerkleTree) calculateNodes() {
 	}
 }
 
+// Proof returns the sibling nodes on the path from the leaf at index to root, and the last one is the root node. It can be verified by Verify
+func (m *MerkleTree) Proof(index int) ([]MerkleNode, error) {
+	if index < 0 || index >= len(m.leafHashes) {
+		return nil, ErrIndexOutOfRange
+	}
+	nodes := m.HashNodes()
+	result := make([]MerkleNode, 0)
+	for n := index; ; n = len(m.leafHashes) + n/2 {
+		if n == len(nodes)-1 {
+			return append(result, MerkleNode{Hash: nodes[n], NodeType: RootNode}), nil
+		} else if n%2 == 1 {
+			result = append(result, MerkleNode{Hash: nodes[n-1], NodeType: LeftNode})
+		} else {
+			result = append(result, MerkleNode{Hash: nodes[n+1], NodeType: RightNode})
+		}
+	}
+}
+
 // FindSiblingNodes 查找伴随节点
 func FindSiblingNodes(src common.Hash, srcNodes []common.Hash) ([]MerkleNode, error) {
 	if srcNodes == nil {
@@ -63,3 +63,49 @@ func Test_FindSiblingNodes(t *testing.T) {
 	valid := Verify(target, m.Root(), sibling)
 	assert.Equal(t, true, valid)
 }
+
+func TestMerkleTree_Proof(t *testing.T) {
+	// empty
+	m := New([]common.Hash{})
+	_, err := m.Proof(0)
+	assert.Equal(t, ErrIndexOutOfRange, err)
+
+	// 1 element
+	m = New([]common.Hash{src[0]})
+	proof, err := m.Proof(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []MerkleNode{{Hash: src[0], NodeType: RootNode}}, proof)
+	assert.Equal(t, true, Verify(src[0], m.Root(), proof))
+
+	// every leaf in 1~5 elements
+	for count := 1; count <= len(src); count++ {
+		m = New(src[:count])
+		root := m.Root()
+		for i := 0; i < count; i++ {
+			proof, err = m.Proof(i)
+			assert.NoError(t, err)
+			assert.Equal(t, RootNode, proof[len(proof)-1].NodeType)
+			assert.Equal(t, true, Verify(src[i], root, proof))
+			// wrong target
+			assert.Equal(t, false, Verify(common.HexToHash("0x1234"), root, proof))
+		}
+		_, err = m.Proof(count)
+		assert.Equal(t, ErrIndexOutOfRange, err)
+	}
+
+	// the same as FindSiblingNodes
+	m = New(src)
+	proof, err = m.Proof(len(src) - 1)
+	assert.NoError(t, err)
+	sibling, err := FindSiblingNodes(src[len(src)-1], m.HashNodes())
+	assert.NoError(t, err)
+	assert.Equal(t, sibling, proof)
+
+	// duplicate leaves
+	m = New([]common.Hash{src[0], src[1], src[0]})
+	proof, err = m.Proof(2)
+	assert.NoError(t, err)
+	assert.Equal(t, true, Verify(src[0], m.Root(), proof))
+	_, err = m.Proof(-1)
+	assert.Equal(t, ErrIndexOutOfRange, err)
+}
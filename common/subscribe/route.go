@@ -14,6 +14,7 @@ const (
 	NewMinedBlock   = "newMinedBlock"
 	NewCurrentBlock = "newCurrentBlock"
 	NewStableBlock  = "newStableBlock"
+	SwitchFork      = "switchFork"
 	NewTx           = "newTx"
 	NewConfirm      = "newConfirm"
 	FetchConfirms   = "fetchConfirm"
@@ -84,7 +85,9 @@ func (r *CentralRouteSub) send(name string, value interface{}) error {
 	defer func() { <-r.names[name].lock }()
 
 	item := r.names[name]
-	cases := item.caseList
+	// copy the cases so that removing the sent cases won't break the subscription list
+	cases := make(caseList, len(item.caseList))
+	copy(cases, item.caseList)
 	vValue := reflect.ValueOf(value)
 	vType := vValue.Type()
 	if vType != item.eType && !vType.Implements(item.eType) {
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/keystore"
+	"github.com/LemoFoundationLtd/lemochain-core/main/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	ErrMissingPassword = errors.New("missing --password")
+	ErrInvalidCount    = errors.New("--count should be greater than 0")
+)
+
+var (
+	accountMnemonicFlag = cli.StringFlag{
+		Name:  "mnemonic",
+		Usage: "BIP39 mnemonic words. A new mnemonic is generated if it is missing",
+	}
+	accountBitsFlag = cli.IntFlag{
+		Name:  "bits",
+		Usage: "Entropy bits of new mnemonic: 128(12 words) to 256(24 words)",
+		Value: keystore.DefaultMnemonicBits,
+	}
+	accountPassphraseFlag = cli.StringFlag{
+		Name:  "passphrase",
+		Usage: "Optional BIP39 passphrase of mnemonic",
+	}
+	accountPathFlag = cli.StringFlag{
+		Name:  "path",
+		Usage: "BIP32 derivation path, such as m/44'/8888'/0'/0/0. --index and --count are ignored if it is set",
+	}
+	accountIndexFlag = cli.UintFlag{
+		Name:  "index",
+		Usage: "The first account number under the BIP44 path m/44'/8888'/0'/0",
+	}
+	accountCountFlag = cli.UintFlag{
+		Name:  "count",
+		Usage: "The count of accounts to derive",
+		Value: 1,
+	}
+	accountShowKeyFlag = cli.BoolFlag{
+		Name:  "showKey",
+		Usage: "Print the private keys",
+	}
+	accountImportFlag = cli.BoolFlag{
+		Name:  "import",
+		Usage: "Import the derived keys into the keystore in data directory",
+	}
+	accountPasswordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "The password to encrypt the imported keys",
+	}
+
+	accountCommand = cli.Command{
+		Name:     "account",
+		Usage:    "Manage hierarchical deterministic accounts",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+Derive accounts from BIP39 mnemonic by BIP32/BIP44 path.`,
+		Subcommands: []cli.Command{
+			{
+				Action: deriveAccountCmd,
+				Name:   "derive",
+				Usage:  "Generate or restore mnemonic, and derive accounts from it",
+				Flags: []cli.Flag{
+					accountMnemonicFlag, accountBitsFlag, accountPassphraseFlag, accountPathFlag, accountIndexFlag,
+					accountCountFlag, accountShowKeyFlag, accountImportFlag, accountPasswordFlag, node.DataDirFlag,
+				},
+			},
+		},
+	}
+)
+
+// derivedAccount is the account derived from mnemonic
+type derivedAccount struct {
+	Path       string         `json:"path"`
+	Address    common.Address `json:"address"`
+	PrivateKey string         `json:"privateKey,omitempty"`
+}
+
+// derivedAccounts is the output of "glemo account derive"
+type derivedAccounts struct {
+	Mnemonic string           `json:"mnemonic,omitempty"`
+	Accounts []derivedAccount `json:"accounts"`
+}
+
+// deriveAccounts derives the account by path, or derives count accounts from the account number start if path is empty
+func deriveAccounts(mnemonic, passphrase, path string, start, count uint32, showKey bool) ([]derivedAccount, error) {
+	var paths []keystore.DerivationPath
+	if path != "" {
+		derivationPath, err := keystore.ParseDerivationPath(path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, derivationPath)
+	} else {
+		if count == 0 {
+			return nil, ErrInvalidCount
+		}
+		if uint64(start)+uint64(count) > uint64(keystore.HardenedKeyStart) {
+			return nil, keystore.ErrInvalidDerivationPath
+		}
+		for i := uint32(0); i < count; i++ {
+			paths = append(paths, keystore.AccountPath(start+i))
+		}
+	}
+
+	result := make([]derivedAccount, 0, len(paths))
+	for _, derivationPath := range paths {
+		privateKey, err := keystore.DeriveKey(mnemonic, passphrase, derivationPath)
+		if err != nil {
+			return nil, err
+		}
+		account := derivedAccount{
+			Path:    derivationPath.String(),
+			Address: crypto.PubkeyToAddress(privateKey.PublicKey),
+		}
+		if showKey {
+			account.PrivateKey = hexutil.Encode(crypto.FromECDSA(privateKey))
+		}
+		result = append(result, account)
+	}
+	return result, nil
+}
+
+func deriveAccountCmd(ctx *cli.Context) error {
+	output := derivedAccounts{}
+	mnemonic := ctx.String(accountMnemonicFlag.Name)
+	if mnemonic == "" {
+		var err error
+		if mnemonic, err = keystore.NewMnemonic(ctx.Int(accountBitsFlag.Name)); err != nil {
+			return err
+		}
+		// print the new mnemonic so that user can back it up
+		output.Mnemonic = mnemonic
+	}
+	passphrase := ctx.String(accountPassphraseFlag.Name)
+	accounts, err := deriveAccounts(mnemonic, passphrase, ctx.String(accountPathFlag.Name), uint32(ctx.Uint(accountIndexFlag.Name)), uint32(ctx.Uint(accountCountFlag.Name)), ctx.Bool(accountShowKeyFlag.Name))
+	if err != nil {
+		return err
+	}
+	output.Accounts = accounts
+
+	if ctx.Bool(accountImportFlag.Name) {
+		password := ctx.String(accountPasswordFlag.Name)
+		if password == "" {
+			return ErrMissingPassword
+		}
+		ks := keystore.NewKeyStore(node.GetKeystorePath(ctx.String(node.DataDirFlag.Name)), keystore.StandardScryptN, keystore.StandardScryptP)
+		for _, account := range accounts {
+			path, _ := keystore.ParseDerivationPath(account.Path)
+			if _, err := ks.ImportMnemonic(mnemonic, passphrase, path, password); err != nil {
+				return err
+			}
+		}
+	}
+	if err := printJSON(output); err != nil {
+		return err
+	}
+	if ctx.Bool(accountImportFlag.Name) {
+		fmt.Printf("%d account(s) are imported into keystore\n", len(accounts))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/keystore"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_deriveAccounts(t *testing.T) {
+	mnemonic, err := keystore.NewMnemonic(keystore.DefaultMnemonicBits)
+	assert.NoError(t, err)
+
+	// derive by account number
+	accounts, err := deriveAccounts(mnemonic, "", "", 3, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(accounts))
+	assert.Equal(t, "m/44'/8888'/0'/0/3", accounts[0].Path)
+	assert.Equal(t, "m/44'/8888'/0'/0/4", accounts[1].Path)
+	assert.Empty(t, accounts[0].PrivateKey)
+	address, _ := keystore.DeriveAddress(mnemonic, "", keystore.AccountPath(4))
+	assert.Equal(t, address, accounts[1].Address)
+
+	// derive by path
+	accounts, err = deriveAccounts(mnemonic, "", "m/44'/8888'/0'/0/4", 0, 0, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(accounts))
+	assert.Equal(t, address, accounts[0].Address)
+	privateKey, err := crypto.HexToECDSA(accounts[0].PrivateKey[2:])
+	assert.NoError(t, err)
+	assert.Equal(t, address, crypto.PubkeyToAddress(privateKey.PublicKey))
+
+	// errors
+	_, err = deriveAccounts(mnemonic, "", "", 0, 0, false)
+	assert.Equal(t, ErrInvalidCount, err)
+	_, err = deriveAccounts(mnemonic, "", "m/x", 0, 1, false)
+	assert.Equal(t, keystore.ErrInvalidDerivationPath, err)
+	_, err = deriveAccounts("invalid mnemonic", "", "", 0, 1, false)
+	assert.Equal(t, keystore.ErrInvalidMnemonic, err)
+}
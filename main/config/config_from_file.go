@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/txpool"
 	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/metrics"
@@ -35,16 +36,27 @@ type ConfigFromFile struct {
 	InterimDuration uint64 `json:"interimDuration"`
 	ConnectionLimit uint64 `json:"connectionLimit"`
 	AlarmUrl        string `json:"alarmUrl"`
+	// DroppedTxKeepTime is the seconds to keep the reasons of dropped transactions
+	DroppedTxKeepTime uint64 `json:"droppedTxKeepTime"`
+	// GasPricePriority makes the miner pack transactions in gas price order instead of arrival order
+	GasPricePriority bool `json:"gasPricePriority"`
+	// TxPoolLimit is the max count of transactions in tx pool
+	TxPoolLimit uint64 `json:"txPoolLimit"`
+	// TxPoolSenderLimit is the max count of transactions from one account in tx pool
+	TxPoolSenderLimit uint64 `json:"txPoolSenderLimit"`
 }
 
 type ConfigFromFileMarshaling struct {
-	ChainID         hexutil.Uint64
-	DeputyCount     hexutil.Uint64
-	SleepTime       hexutil.Uint64
-	Timeout         hexutil.Uint64
-	TermDuration    hexutil.Uint64
-	InterimDuration hexutil.Uint64
-	ConnectionLimit hexutil.Uint64
+	ChainID           hexutil.Uint64
+	DeputyCount       hexutil.Uint64
+	SleepTime         hexutil.Uint64
+	Timeout           hexutil.Uint64
+	TermDuration      hexutil.Uint64
+	InterimDuration   hexutil.Uint64
+	ConnectionLimit   hexutil.Uint64
+	DroppedTxKeepTime hexutil.Uint64
+	TxPoolLimit       hexutil.Uint64
+	TxPoolSenderLimit hexutil.Uint64
 }
 
 func WriteConfigFile(dir string, cfg *ConfigFromFile) error {
@@ -125,6 +137,9 @@ func (c *ConfigFromFile) Check() {
 	if c.InterimDuration > 0 {
 		params.InterimDuration = uint32(c.InterimDuration)
 	}
+	if c.DroppedTxKeepTime > 0 {
+		txpool.DroppedTxKeepTime = c.DroppedTxKeepTime
+	}
 	if len(c.AlarmUrl) > 0 { // if configured, then start metrics and alarm system client
 		metrics.AlarmUrl = c.AlarmUrl
 	}
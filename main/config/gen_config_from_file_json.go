@@ -14,14 +14,18 @@ var _ = (*ConfigFromFileMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (c ConfigFromFile) MarshalJSON() ([]byte, error) {
 	type ConfigFromFile struct {
-		ChainID         hexutil.Uint64 `json:"chainID"        gencodec:"required"`
-		DeputyCount     hexutil.Uint64 `json:"deputyCount"    gencodec:"required"`
-		SleepTime       hexutil.Uint64 `json:"sleepTime"`
-		Timeout         hexutil.Uint64 `json:"timeout"`
-		TermDuration    hexutil.Uint64 `json:"termDuration"`
-		InterimDuration hexutil.Uint64 `json:"interimDuration"`
-		ConnectionLimit hexutil.Uint64 `json:"connectionLimit"`
-		AlarmUrl        string         `json:"alarmUrl"`
+		ChainID           hexutil.Uint64 `json:"chainID"        gencodec:"required"`
+		DeputyCount       hexutil.Uint64 `json:"deputyCount"    gencodec:"required"`
+		SleepTime         hexutil.Uint64 `json:"sleepTime"`
+		Timeout           hexutil.Uint64 `json:"timeout"`
+		TermDuration      hexutil.Uint64 `json:"termDuration"`
+		InterimDuration   hexutil.Uint64 `json:"interimDuration"`
+		ConnectionLimit   hexutil.Uint64 `json:"connectionLimit"`
+		AlarmUrl          string         `json:"alarmUrl"`
+		DroppedTxKeepTime hexutil.Uint64 `json:"droppedTxKeepTime"`
+		GasPricePriority  bool           `json:"gasPricePriority"`
+		TxPoolLimit       hexutil.Uint64 `json:"txPoolLimit"`
+		TxPoolSenderLimit hexutil.Uint64 `json:"txPoolSenderLimit"`
 	}
 	var enc ConfigFromFile
 	enc.ChainID = hexutil.Uint64(c.ChainID)
@@ -32,20 +36,28 @@ func (c ConfigFromFile) MarshalJSON() ([]byte, error) {
 	enc.InterimDuration = hexutil.Uint64(c.InterimDuration)
 	enc.ConnectionLimit = hexutil.Uint64(c.ConnectionLimit)
 	enc.AlarmUrl = c.AlarmUrl
+	enc.DroppedTxKeepTime = hexutil.Uint64(c.DroppedTxKeepTime)
+	enc.GasPricePriority = c.GasPricePriority
+	enc.TxPoolLimit = hexutil.Uint64(c.TxPoolLimit)
+	enc.TxPoolSenderLimit = hexutil.Uint64(c.TxPoolSenderLimit)
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (c *ConfigFromFile) UnmarshalJSON(input []byte) error {
 	type ConfigFromFile struct {
-		ChainID         *hexutil.Uint64 `json:"chainID"        gencodec:"required"`
-		DeputyCount     *hexutil.Uint64 `json:"deputyCount"    gencodec:"required"`
-		SleepTime       *hexutil.Uint64 `json:"sleepTime"`
-		Timeout         *hexutil.Uint64 `json:"timeout"`
-		TermDuration    *hexutil.Uint64 `json:"termDuration"`
-		InterimDuration *hexutil.Uint64 `json:"interimDuration"`
-		ConnectionLimit *hexutil.Uint64 `json:"connectionLimit"`
-		AlarmUrl        *string         `json:"alarmUrl"`
+		ChainID           *hexutil.Uint64 `json:"chainID"        gencodec:"required"`
+		DeputyCount       *hexutil.Uint64 `json:"deputyCount"    gencodec:"required"`
+		SleepTime         *hexutil.Uint64 `json:"sleepTime"`
+		Timeout           *hexutil.Uint64 `json:"timeout"`
+		TermDuration      *hexutil.Uint64 `json:"termDuration"`
+		InterimDuration   *hexutil.Uint64 `json:"interimDuration"`
+		ConnectionLimit   *hexutil.Uint64 `json:"connectionLimit"`
+		AlarmUrl          *string         `json:"alarmUrl"`
+		DroppedTxKeepTime *hexutil.Uint64 `json:"droppedTxKeepTime"`
+		GasPricePriority  *bool           `json:"gasPricePriority"`
+		TxPoolLimit       *hexutil.Uint64 `json:"txPoolLimit"`
+		TxPoolSenderLimit *hexutil.Uint64 `json:"txPoolSenderLimit"`
 	}
 	var dec ConfigFromFile
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -77,5 +89,17 @@ func (c *ConfigFromFile) UnmarshalJSON(input []byte) error {
 	if dec.AlarmUrl != nil {
 		c.AlarmUrl = *dec.AlarmUrl
 	}
+	if dec.DroppedTxKeepTime != nil {
+		c.DroppedTxKeepTime = uint64(*dec.DroppedTxKeepTime)
+	}
+	if dec.GasPricePriority != nil {
+		c.GasPricePriority = *dec.GasPricePriority
+	}
+	if dec.TxPoolLimit != nil {
+		c.TxPoolLimit = uint64(*dec.TxPoolLimit)
+	}
+	if dec.TxPoolSenderLimit != nil {
+		c.TxPoolSenderLimit = uint64(*dec.TxPoolSenderLimit)
+	}
 	return nil
 }
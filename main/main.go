@@ -63,6 +63,8 @@ func init() {
 		attachCommand,
 		createaccountCommand,  // create an account when run "./glemo createaccount"
 		createanodekeyCommand, // create nodekey and nodeID when run "./glemo createnodekey"
+		txCommand,             // build, sign, inspect and send transactions offline when run "./glemo tx"
+		accountCommand,        // derive HD wallet accounts from mnemonic when run "./glemo account derive"
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 	app.Flags = append(app.Flags, nodeFlags...)
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,16 +10,20 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/miner"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/txpool"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
 	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/keystore"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/common/subscribe"
 	"github.com/LemoFoundationLtd/lemochain-core/network"
 	"github.com/LemoFoundationLtd/lemochain-core/network/p2p"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
 	"math/big"
 	"runtime"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -35,16 +40,21 @@ var (
 	ErrTxChainID      = errors.New("tx chainID is incorrect")
 	ErrInputParams    = errors.New("input params incorrect")
 	ErrTxTo           = errors.New("transaction to is incorrect")
+	ErrNotCandidate   = errors.New("the account is not a candidate")
 )
 
+// defaultUnlockDuration is the seconds to keep the account unlocked if the duration is not set
+const defaultUnlockDuration = uint64(300)
+
 // Private
 type PrivateAccountAPI struct {
-	manager *account.Manager
+	manager  *account.Manager
+	keystore *keystore.KeyStore
 }
 
 // NewPrivateAccountAPI
-func NewPrivateAccountAPI(m *account.Manager) *PrivateAccountAPI {
-	return &PrivateAccountAPI{m}
+func NewPrivateAccountAPI(m *account.Manager, ks *keystore.KeyStore) *PrivateAccountAPI {
+	return &PrivateAccountAPI{m, ks}
 }
 
 //go:generate gencodec -type LemoAccount -out gen_lemo_account_json.go
@@ -66,6 +76,44 @@ func (a *PrivateAccountAPI) NewKeyPair() (*LemoAccount, error) {
 	return acc, nil
 }
 
+// NewAccount creates a new account in keystore. The private key is encrypted by the password
+func (a *PrivateAccountAPI) NewAccount(password string) (common.Address, error) {
+	return a.keystore.NewAccount(password)
+}
+
+// ListAccounts returns all accounts in keystore
+func (a *PrivateAccountAPI) ListAccounts() ([]common.Address, error) {
+	return a.keystore.Accounts()
+}
+
+// Unlock decrypts the private key of account and keeps it in memory for duration seconds. It is 300 seconds if duration is not set, and it is unlocked until the node stops if duration is 0
+func (a *PrivateAccountAPI) Unlock(lemoAddress string, password string, duration *uint64) error {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return err
+	}
+	seconds := defaultUnlockDuration
+	if duration != nil {
+		seconds = *duration
+	}
+	return a.keystore.Unlock(address, password, time.Duration(seconds)*time.Second)
+}
+
+// Lock removes the private key of account from memory
+func (a *PrivateAccountAPI) Lock(lemoAddress string) error {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return err
+	}
+	a.keystore.Lock(address)
+	return nil
+}
+
+// SignTx signs the transaction by its sender account in keystore. The account must be unlocked
+func (a *PrivateAccountAPI) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	return a.keystore.SignTx(tx)
+}
+
 // PublicAccountAPI API for access to account information
 type PublicAccountAPI struct {
 	manager *account.Manager
@@ -253,6 +301,190 @@ func (c *PublicChainAPI) GetCandidateTop30() []*CandidateInfo {
 	return candidateList
 }
 
+//go:generate gencodec -type CandidateListRes --field-override candidateListResMarshaling -out gen_candidate_list_res_json.go
+type CandidateListRes struct {
+	CandidateList []*CandidateInfo `json:"candidateList" gencodec:"required"`
+	Total         uint32           `json:"total" gencodec:"required"`
+}
+
+type candidateListResMarshaling struct {
+	Total hexutil.Uint32
+}
+
+// GetCandidateList get the candidates by page. They are sorted by votes if sortByVotes is true, or else by register order
+func (c *PublicChainAPI) GetCandidateList(index int, size int, sortByVotes bool) (*CandidateListRes, error) {
+	if (index < 0) || (size > 200) || (size <= 0) {
+		return nil, store.ErrArgInvalid
+	}
+	var (
+		addresses []common.Address
+		total     uint32
+		err       error
+	)
+	if sortByVotes {
+		addresses, total, err = c.getCandidatesSortedByVotes(index, size)
+	} else {
+		addresses, total, err = c.chain.GetCandidatesPage(index, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	candidateList := make([]*CandidateInfo, 0, len(addresses))
+	for _, address := range addresses {
+		candidateList = append(candidateList, c.newCandidateInfo(address))
+	}
+	return &CandidateListRes{
+		CandidateList: candidateList,
+		Total:         total,
+	}, nil
+}
+
+// getCandidatesSortedByVotes load all candidates and sort them by votes from high to low, then returns a page of them
+func (c *PublicChainAPI) getCandidatesSortedByVotes(index int, size int) ([]common.Address, uint32, error) {
+	addresses, err := c.chain.GetAllCandidates()
+	if err != nil {
+		return nil, 0, err
+	}
+	votes := make(map[common.Address]*big.Int, len(addresses))
+	for _, address := range addresses {
+		votes[address] = c.chain.AccountManager().GetCanonicalAccount(address).GetVotes()
+	}
+	// the candidates with same votes are sorted by address, so that the pages are stable
+	sort.Slice(addresses, func(i, j int) bool {
+		if cmp := votes[addresses[i]].Cmp(votes[addresses[j]]); cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	total := len(addresses)
+	if index >= total {
+		return make([]common.Address, 0), uint32(total), nil
+	}
+	end := index + size
+	if end > total {
+		end = total
+	}
+	return addresses[index:end], uint32(total), nil
+}
+
+// GetCandidate get the profile and votes of a candidate
+func (c *PublicChainAPI) GetCandidate(lemoAddress string) (*CandidateInfo, error) {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	// the deregistered candidate has the profile too
+	profile := c.chain.AccountManager().GetCanonicalAccount(address).GetCandidate()
+	if profile[types.CandidateKeyIsCandidate] != types.IsCandidateNode {
+		return nil, ErrNotCandidate
+	}
+	return c.newCandidateInfo(address), nil
+}
+
+// newCandidateInfo read the profile and votes of candidate from stable account
+func (c *PublicChainAPI) newCandidateInfo(address common.Address) *CandidateInfo {
+	candidateAccount := c.chain.AccountManager().GetCanonicalAccount(address)
+	profile := candidateAccount.GetCandidate()
+	if profile == nil {
+		profile = make(map[string]string)
+	}
+	return &CandidateInfo{
+		CandidateAddress: address.String(),
+		Votes:            candidateAccount.GetVotes().String(),
+		Profile:          profile,
+	}
+}
+
+//go:generate gencodec -type ForksRes --field-override forksResMarshaling -out gen_forks_res_json.go
+type ForksRes struct {
+	StableHash   common.Hash   `json:"stableHash" gencodec:"required"`
+	StableHeight uint32        `json:"stableHeight" gencodec:"required"`
+	CurrentHash  common.Hash   `json:"currentHash" gencodec:"required"`
+	Forks        []*store.Fork `json:"forks" gencodec:"required"`
+}
+
+type forksResMarshaling struct {
+	StableHeight hexutil.Uint32
+}
+
+// GetForks get every branch from the stable block to the unstable leaf block, and which branch the current block is in
+func (c *PublicChainAPI) GetForks() *ForksRes {
+	stable := c.chain.StableBlock()
+	current := c.chain.CurrentBlock()
+	return &ForksRes{
+		StableHash:   stable.Hash(),
+		StableHeight: stable.Height(),
+		CurrentHash:  current.Hash(),
+		Forks:        c.chain.GetForks(current.Hash()),
+	}
+}
+
+//go:generate gencodec -type ConfirmsRes --field-override confirmsResMarshaling -out gen_confirms_res_json.go
+type ConfirmsRes struct {
+	Hash     common.Hash `json:"hash" gencodec:"required"`
+	Height   uint32      `json:"height" gencodec:"required"`
+	IsStable bool        `json:"isStable" gencodec:"required"`
+	// Threshold is the signers count which makes the block stable
+	Threshold      uint32            `json:"threshold" gencodec:"required"`
+	DeputyCount    uint32            `json:"deputyCount" gencodec:"required"`
+	Signers        types.DeputyNodes `json:"signers" gencodec:"required"`
+	MissingSigners types.DeputyNodes `json:"missingSigners" gencodec:"required"`
+}
+
+type confirmsResMarshaling struct {
+	Height      hexutil.Uint32
+	Threshold   hexutil.Uint32
+	DeputyCount hexutil.Uint32
+}
+
+// GetConfirms get the deputies who have signed the block (include the miner) and the deputies who haven't
+func (c *PublicChainAPI) GetConfirms(hash string) (*ConfirmsRes, error) {
+	if len(common.FromHex(hash)) != common.HashLength {
+		return nil, ErrInputParams
+	}
+	block := c.chain.GetBlockByHash(common.HexToHash(hash))
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	signers, missingSigners, err := c.chain.GetConfirmSigners(block)
+	if err != nil {
+		return nil, err
+	}
+	dm := c.chain.DeputyManager()
+	return &ConfirmsRes{
+		Hash:           block.Hash(),
+		Height:         block.Height(),
+		IsStable:       block.Height() <= c.chain.StableBlock().Height(),
+		Threshold:      dm.TwoThirdDeputyCount(block.Height()),
+		DeputyCount:    uint32(len(signers) + len(missingSigners)),
+		Signers:        signers,
+		MissingSigners: missingSigners,
+	}, nil
+}
+
+// GetTermList get all deputy terms. The last one may be not started yet
+func (c *PublicChainAPI) GetTermList() []*deputynode.TermRecord {
+	return c.chain.DeputyManager().GetTermList()
+}
+
+// GetTermByHeight get the deputy term which in charge of consensus the block at height
+func (c *PublicChainAPI) GetTermByHeight(height uint32) (*deputynode.TermRecord, error) {
+	return c.chain.DeputyManager().GetTermByHeight(height)
+}
+
+// GetNextTermPreview get the candidates which would be the deputies of next term if the snapshot is taken on current stable block
+func (c *PublicChainAPI) GetNextTermPreview() *deputynode.TermRecord {
+	stable := c.chain.StableBlock()
+	// the next term starts after the interim duration of next snapshot block
+	snapshotHeight := (stable.Height()/params.TermDuration + 1) * params.TermDuration
+	return &deputynode.TermRecord{
+		TermIndex: deputynode.GetTermIndexByHeight(snapshotHeight + params.InterimDuration + 1),
+		Nodes:     c.chain.LoadStableTopCandidates(),
+	}
+}
+
 // GetBlockByNumber get block information by height
 func (c *PublicChainAPI) GetBlockByHeight(height uint32, withBody bool) *types.Block {
 	if withBody {
@@ -380,6 +612,11 @@ func (m *PublicMineAPI) GetLeastGasPrice() string {
 	return params.MinGasPrice.String()
 }
 
+// GetPackingGasPrice get the least gas price of transactions which could be packed in next block. It is the least gas price setting if the tx pool is not full
+func (m *PublicMineAPI) GetPackingGasPrice() string {
+	return m.miner.GetPackingGasPrice().String()
+}
+
 // IsMining
 func (m *PublicMineAPI) IsMining() bool {
 	return m.miner.IsMining()
@@ -391,6 +628,22 @@ func (m *PublicMineAPI) Miner() string {
 	return address.String()
 }
 
+// maxScheduleCount is the max count of mine slots which can be returned by mine_getSchedule
+const maxScheduleCount = 1000
+
+// GetSchedule get the next count expected miners and their mine windows, if there is no new block
+func (m *PublicMineAPI) GetSchedule(count int) ([]*miner.MineSlot, error) {
+	if count <= 0 || count > maxScheduleCount {
+		return nil, ErrInputParams
+	}
+	return m.miner.GetSchedule(count)
+}
+
+// GetMyNextSlot get the next mine window of this node, if there is no new block
+func (m *PublicMineAPI) GetMyNextSlot() (*miner.MineSlot, error) {
+	return m.miner.GetMyNextSlot()
+}
+
 // PrivateNetAPI
 type PrivateNetAPI struct {
 	node *Node
@@ -487,42 +740,247 @@ func (t *PublicTxAPI) SendTx(tx *types.Transaction) (common.Hash, error) {
 		log.Errorf("VerifyTxBody error: %s", err)
 		return common.Hash{}, err
 	}
-	if t.node.txPool.RecvTx(tx) {
+	if err := t.node.txPool.AddTx(tx); err == nil {
 		// 广播交易
 		go subscribe.Send(subscribe.NewTx, tx)
+	} else if err != txpool.ErrTxIsExist {
+		return common.Hash{}, err
 	}
 	return tx.Hash(), nil
 }
 
+// ProposeMultisig saves the transaction of multisig account for collecting signatures from other signers. It must be signed by one of the signers
+func (t *PublicTxAPI) ProposeMultisig(tx *types.Transaction) (*txpool.MultisigTx, error) {
+	if err := tx.VerifyTxBody(t.node.ChainID(), uint64(time.Now().Unix()), false); err != nil {
+		log.Errorf("VerifyTxBody error: %s", err)
+		return nil, err
+	}
+	return t.node.multisig.Propose(tx)
+}
+
+// AddSignature adds a signature to the pending multisig transaction by its sign hash. The transaction is sent once the signers' weight reaches the threshold.
+// If sending is failed, the transaction is kept in pending list and can be sent again by proposing it
+func (t *PublicTxAPI) AddSignature(hash common.Hash, sig hexutil.Bytes) (*txpool.MultisigTx, error) {
+	return t.node.multisig.AddSignature(hash, sig)
+}
+
+// GetPendingMultisig returns the multisig transactions of the account which are waiting for signatures
+func (t *PublicTxAPI) GetPendingMultisig(lemoAddress string) ([]*txpool.MultisigTx, error) {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	return t.node.multisig.Get(address), nil
+}
+
+// PrivateTxAPI API for sending transactions by the accounts in keystore
+type PrivateTxAPI struct {
+	node *Node
+}
+
+// NewPrivateTxAPI
+func NewPrivateTxAPI(node *Node) *PrivateTxAPI {
+	return &PrivateTxAPI{node}
+}
+
+// SendTxFromAccount signs the transaction by its sender account in keystore, then sends it. The account must be unlocked
+func (t *PrivateTxAPI) SendTxFromAccount(tx *types.Transaction) (common.Hash, error) {
+	signedTx, err := t.node.keystore.SignTx(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return NewPublicTxAPI(t.node).SendTx(signedTx)
+}
+
+const (
+	// TxStatusStable means the transaction is packed in stable block and executed successfully
+	TxStatusStable = "stable"
+	// TxStatusFailed means the transaction is packed in stable block but the execution is failed
+	TxStatusFailed = "failed"
+)
+
+// GetTxStatus get the status of transaction. It may be pending in tx pool, packed in unstable blocks, stable, failed, expired or dropped
+func (t *PublicTxAPI) GetTxStatus(txHash string) (*txpool.TxStatus, error) {
+	if len(common.FromHex(txHash)) != common.HashLength {
+		log.Warnf("Hash is incorrect, Hash: %s", txHash)
+		return nil, ErrInputParams
+	}
+	hash := common.HexToHash(txHash)
+	txDetail, err := t.node.db.GetBizDatabase().GetTxByHash(hash)
+	if err == nil {
+		status := &txpool.TxStatus{Status: TxStatusStable, BlockHashes: []common.Hash{txDetail.BlockHash}}
+		if receipt, err := t.node.db.GetReceipt(hash); err == nil && receipt.Status == types.ReceiptStatusFailed {
+			status.Status = TxStatusFailed
+			status.Reason = receipt.VmErr
+		}
+		return status, nil
+	} else if err != store.ErrNotExist {
+		return nil, err
+	}
+	return t.node.txPool.GetTxStatus(hash, uint32(time.Now().Unix())), nil
+}
+
 // PendingTx
 func (t *PublicTxAPI) PendingTx(size int) []*types.Transaction {
 	return t.node.txPool.Get(uint32(time.Now().Unix()), size)
 }
 
+//go:generate gencodec -type TxListRes --field-override txListResMarshaling -out gen_tx_list_res_json.go
+type TxListRes struct {
+	VTransactions []*store.VTransaction `json:"txList" gencodec:"required"`
+	Total         uint32                `json:"total" gencodec:"required"`
+}
+
+type txListResMarshaling struct {
+	Total hexutil.Uint32
+}
+
+// GetTxByHash pull the transaction detail from stable chain by tx hash
+func (t *PublicTxAPI) GetTxByHash(txHash string) (*store.VTransactionDetail, error) {
+	if len(common.FromHex(txHash)) != common.HashLength {
+		log.Warnf("Hash is incorrect, Hash: %s", txHash)
+		return nil, ErrInputParams
+	}
+	txDetail, err := t.node.db.GetBizDatabase().GetTxByHash(common.HexToHash(txHash))
+	if err == store.ErrNotExist {
+		return nil, nil
+	}
+	return txDetail, err
+}
+
+// GetReceipt get the execution result of a transaction in stable block by transaction hash
+func (t *PublicTxAPI) GetReceipt(txHash string) (*types.Receipt, error) {
+	if len(common.FromHex(txHash)) != common.HashLength {
+		log.Warnf("Hash is incorrect, Hash: %s", txHash)
+		return nil, ErrInputParams
+	}
+	receipt, err := t.node.db.GetReceipt(common.HexToHash(txHash))
+	if err == store.ErrNotExist {
+		return nil, nil
+	}
+	return receipt, err
+}
+
+// GetTxListByAddress pull the transactions of an account by page. The transactions are sorted from old to new
+func (t *PublicTxAPI) GetTxListByAddress(lemoAddress string, index int, size int) (*TxListRes, error) {
+	src, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	txs, total, err := t.node.db.GetBizDatabase().GetTxByAddr(src, index, size)
+	if err != nil {
+		return nil, err
+	}
+	return &TxListRes{
+		VTransactions: txs,
+		Total:         total,
+	}, nil
+}
+
+// GetTxListByAddressAndAssetCode pull the transactions of an account which operate the asset by page
+func (t *PublicTxAPI) GetTxListByAddressAndAssetCode(lemoAddress string, assetCode common.Hash, index int, size int) (*TxListRes, error) {
+	src, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	txs, total, err := t.node.db.GetBizDatabase().GetTxByAssetCode(src, assetCode, index, size)
+	if err != nil {
+		return nil, err
+	}
+	return &TxListRes{
+		VTransactions: txs,
+		Total:         total,
+	}, nil
+}
+
+// GetTxListByAddressAndAssetId pull the transactions of an account which operate the asset equity by page
+func (t *PublicTxAPI) GetTxListByAddressAndAssetId(lemoAddress string, assetId common.Hash, index int, size int) (*TxListRes, error) {
+	src, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	txs, total, err := t.node.db.GetBizDatabase().GetTxByAssetId(src, assetId, index, size)
+	if err != nil {
+		return nil, err
+	}
+	return &TxListRes{
+		VTransactions: txs,
+		Total:         total,
+	}, nil
+}
+
+// EstimateGas returns the minimal gas limit to execute the transaction on current block. The signatures of transaction are not required
+func (t *PublicTxAPI) EstimateGas(tx *types.Transaction) (hexutil.Uint64, error) {
+	if tx == nil {
+		return 0, ErrInputParams
+	}
+	gas, err := t.node.chain.TxProcessor().EstimateGas(t.node.chain.CurrentBlock(), tx)
+	return hexutil.Uint64(gas), err
+}
+
+//go:generate gencodec -type InternalTxListRes --field-override internalTxListResMarshaling -out gen_internal_tx_list_res_json.go
+type InternalTxListRes struct {
+	InternalTxs []*store.VInternalTx `json:"internalTxList" gencodec:"required"`
+	Total       uint32               `json:"total" gencodec:"required"`
+}
+
+type internalTxListResMarshaling struct {
+	Total hexutil.Uint32
+}
+
+// GetInternalTxListByAddress pull the value transfers made by contracts which are related to the account by page
+func (t *PublicTxAPI) GetInternalTxListByAddress(lemoAddress string, index int, size int) (*InternalTxListRes, error) {
+	src, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	internalTxs, total, err := t.node.db.GetBizDatabase().GetInternalTxByAddr(src, index, size)
+	if err != nil {
+		return nil, err
+	}
+	return &InternalTxListRes{
+		InternalTxs: internalTxs,
+		Total:       total,
+	}, nil
+}
+
 // ReadContract read variables in a contract includes the return value of a function.
 func (t *PublicTxAPI) ReadContract(to *common.Address, data hexutil.Bytes) (string, error) {
 	if to == nil {
 		return "", ErrInputParams
 	}
 	accM := account.NewReadOnlyManager(t.node.Db(), true)
-	result, err := t.doCallTransaction(to, accM, data, 5*time.Second)
+	// get current stableBlock
+	currentBlock := t.node.chain.CurrentBlock()
+	log.Infof("Current block height = %v", currentBlock.Height())
+	result, err := t.doCallTransaction(to, accM, currentBlock.Header, data, 5*time.Second)
+	return common.ToHex(result), err
+}
+
+// ReadContractAt read variables in a contract on the state of the block. The block is specified by hash or height.
+func (t *PublicTxAPI) ReadContractAt(to *common.Address, data hexutil.Bytes, blockHashOrHeight string) (string, error) {
+	if to == nil {
+		return "", ErrInputParams
+	}
+	block, err := t.node.blockByHashOrHeight(blockHashOrHeight)
+	if err != nil {
+		return "", err
+	}
+	accM := account.NewReadOnlyManager(t.node.Db(), false)
+	result, err := t.doCallTransaction(to, accM, block.Header, data, 5*time.Second)
 	return common.ToHex(result), err
 }
 
 // doCallTransaction
-func (t *PublicTxAPI) doCallTransaction(to *common.Address, accM *account.ReadOnlyManager, data hexutil.Bytes, timeout time.Duration) ([]byte, error) {
+func (t *PublicTxAPI) doCallTransaction(to *common.Address, accM *account.ReadOnlyManager, header *types.Header, data hexutil.Bytes, timeout time.Duration) ([]byte, error) {
 	t.node.lock.Lock()
 	defer t.node.lock.Unlock()
 
 	defer func(start time.Time) {
 		log.Debug("Executing EVM call finished", "cost time", time.Since(start))
 	}(time.Now())
-	// get current stableBlock
-	currentBlock := t.node.chain.CurrentBlock()
-	log.Infof("Current block height = %v", currentBlock.Height())
-	currentHeader := currentBlock.Header
 	p := t.node.chain.TxProcessor()
-	ret, err := p.ReadContract(accM, currentHeader, *to, data, timeout)
+	ret, err := p.ReadContract(accM, header, *to, data, timeout)
 
 	return ret, err
 }
@@ -1,19 +1,44 @@
 package node
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/chain"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/account"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/deputynode"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/testchain"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/txpool"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/vm"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/keystore"
+	"github.com/LemoFoundationLtd/lemochain-core/common/merkle"
+	"github.com/LemoFoundationLtd/lemochain-core/common/subscribe"
+	"github.com/LemoFoundationLtd/lemochain-core/network/rpc"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+	"github.com/LemoFoundationLtd/lemochain-core/store/protocol"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"math/big"
+	"os"
 	"testing"
 	"time"
 )
 
+// newTestNode creates a node on the chain made by testchain.NewTestChain
+func newTestNode(bc *chain.BlockChain, db protocol.ChainDB) *Node {
+	return &Node{
+		chainID: 200,
+		chain:   bc,
+		db:      db,
+	}
+}
+
 // TestAccountAPI_api account api test
 func TestAccountAPI_api(t *testing.T) {
 	bc, db := testchain.NewTestChain()
@@ -21,7 +46,7 @@ func TestAccountAPI_api(t *testing.T) {
 
 	am := bc.AccountManager()
 	acc := NewPublicAccountAPI(am)
-	priAcc := NewPrivateAccountAPI(am)
+	priAcc := NewPrivateAccountAPI(am, nil)
 	// Create key pair
 	addressKeyPair, err := priAcc.NewKeyPair()
 	assert.NoError(t, err)
@@ -48,6 +73,123 @@ func TestAccountAPI_api(t *testing.T) {
 	assert.Equal(t, common.ErrInvalidAddress, err)
 }
 
+func TestChainAPI_GetCandidateList(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+	c := NewPublicChainAPI(bc)
+
+	// invalid page
+	_, err := c.GetCandidateList(-1, 10, false)
+	assert.Equal(t, store.ErrArgInvalid, err)
+	_, err = c.GetCandidateList(0, 0, true)
+	assert.Equal(t, store.ErrArgInvalid, err)
+
+	all, err := db.GetAllCandidates()
+	assert.NoError(t, err)
+	list, err := c.GetCandidateList(0, 200, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(len(all)), list.Total)
+	assert.Len(t, list.CandidateList, len(all))
+	addresses := make([]string, 0, len(all))
+	for _, info := range list.CandidateList {
+		addresses = append(addresses, info.CandidateAddress)
+		assert.Equal(t, types.IsCandidateNode, info.Profile[types.CandidateKeyIsCandidate])
+	}
+	for _, address := range all {
+		assert.Contains(t, addresses, address.String())
+	}
+
+	// sort by votes
+	sorted, err := c.GetCandidateList(0, 200, true)
+	assert.NoError(t, err)
+	assert.Equal(t, list.Total, sorted.Total)
+	for i := 1; i < len(sorted.CandidateList); i++ {
+		prev, _ := new(big.Int).SetString(sorted.CandidateList[i-1].Votes, 10)
+		votes, _ := new(big.Int).SetString(sorted.CandidateList[i].Votes, 10)
+		assert.True(t, prev.Cmp(votes) >= 0)
+	}
+	page, err := c.GetCandidateList(1, 1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, sorted.Total, page.Total)
+	if len(sorted.CandidateList) > 1 {
+		assert.Equal(t, sorted.CandidateList[1], page.CandidateList[0])
+	}
+	page, err = c.GetCandidateList(int(sorted.Total), 10, true)
+	assert.NoError(t, err)
+	assert.Empty(t, page.CandidateList)
+
+	// single candidate
+	_, err = c.GetCandidate("0x015780F8456F9c1532645087a19DcF9a7e0c7F97")
+	assert.Equal(t, common.ErrInvalidAddress, err)
+	_, err = c.GetCandidate(common.HexToAddress("0x1234").String())
+	assert.Equal(t, ErrNotCandidate, err)
+	info, err := c.GetCandidate(testchain.FounderAddr.String())
+	assert.NoError(t, err)
+	assert.Equal(t, testchain.FounderAddr.String(), info.CandidateAddress)
+	assert.NotEmpty(t, info.Profile[types.CandidateKeyHost])
+}
+
+func TestChainAPI_Term(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+	c := NewPublicChainAPI(bc)
+
+	terms := c.GetTermList()
+	assert.Len(t, terms, 1)
+	assert.Equal(t, uint32(0), terms[0].TermIndex)
+	assert.Equal(t, bc.Genesis().DeputyNodes, terms[0].Nodes)
+
+	term, err := c.GetTermByHeight(1)
+	assert.NoError(t, err)
+	assert.Equal(t, terms[0], term)
+	_, err = c.GetTermByHeight(params.TermDuration*2 + params.InterimDuration + 1)
+	assert.Equal(t, deputynode.ErrQueryFutureTerm, err)
+
+	preview := c.GetNextTermPreview()
+	assert.Equal(t, uint32(1), preview.TermIndex)
+	assert.Equal(t, deputynode.GetTermIndexByHeight(params.TermDuration+params.InterimDuration+1), preview.TermIndex)
+	assert.NotEmpty(t, preview.Nodes)
+	assert.True(t, len(preview.Nodes) <= bc.DeputyManager().DeputyCount)
+	for i, node := range preview.Nodes {
+		assert.Equal(t, uint32(i), node.Rank)
+	}
+}
+
+func TestChainAPI_GetForks(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+	c := NewPublicChainAPI(bc)
+
+	forks := c.GetForks()
+	assert.Equal(t, bc.StableBlock().Hash(), forks.StableHash)
+	assert.Equal(t, bc.StableBlock().Height(), forks.StableHeight)
+	assert.Equal(t, bc.CurrentBlock().Hash(), forks.CurrentHash)
+	for _, fork := range forks.Forks {
+		assert.Equal(t, forks.StableHeight+1, fork.Blocks[0].Height)
+	}
+}
+
+func TestChainAPI_GetConfirms(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+	c := NewPublicChainAPI(bc)
+
+	genesis := bc.Genesis()
+	confirms, err := c.GetConfirms(genesis.Hash().Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, genesis.Hash(), confirms.Hash)
+	assert.True(t, confirms.IsStable)
+	assert.Equal(t, bc.DeputyManager().TwoThirdDeputyCount(0), confirms.Threshold)
+	assert.Equal(t, uint32(len(genesis.DeputyNodes)), confirms.DeputyCount)
+	assert.Empty(t, confirms.Signers)
+	assert.Equal(t, len(genesis.DeputyNodes), len(confirms.MissingSigners))
+
+	_, err = c.GetConfirms("0x1234")
+	assert.Equal(t, ErrInputParams, err)
+	_, err = c.GetConfirms(common.Hash{0x12}.Hex())
+	assert.Equal(t, ErrBlockNotFound, err)
+}
+
 // TestChainAPI_api chain api test
 func TestChainAPI_api(t *testing.T) {
 	bc, db := testchain.NewTestChain()
@@ -133,6 +275,485 @@ func TestTxAPI_api(t *testing.T) {
 	assert.Equal(t, tx.Hash(), sendTxHash)
 }
 
+func TestAccountAPI_keystore(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	node := newTestNode(bc, db)
+	node.chainID = 100
+	node.txPool = txpool.NewTxPool()
+	node.keystore = keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	accAPI := NewPrivateAccountAPI(bc.AccountManager(), node.keystore)
+	txAPI := NewPrivateTxAPI(node)
+
+	address, err := accAPI.NewAccount("password")
+	assert.NoError(t, err)
+	accounts, err := accAPI.ListAccounts()
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{address}, accounts)
+
+	// locked
+	testTx := types.NewTransaction(address, common.HexToAddress("0x1"), common.Big1, 100, big.NewInt(1000000000), []byte{12}, 0, 100, uint64(time.Now().Unix()+60*30), "aa", string("send a Tx"))
+	_, err = accAPI.SignTx(testTx)
+	assert.Equal(t, keystore.ErrLocked, err)
+	_, err = txAPI.SendTxFromAccount(testTx)
+	assert.Equal(t, keystore.ErrLocked, err)
+	assert.Equal(t, keystore.ErrDecrypt, accAPI.Unlock(address.String(), "wrong", nil))
+	assert.Error(t, accAPI.Unlock("0x1", "password", nil))
+
+	// unlocked
+	assert.NoError(t, accAPI.Unlock(address.String(), "password", nil))
+	signedTx, err := accAPI.SignTx(testTx)
+	assert.NoError(t, err)
+	signers, err := types.MakeSigner().GetSigners(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{address}, signers)
+	hash, err := txAPI.SendTxFromAccount(testTx)
+	assert.NoError(t, err)
+	assert.Equal(t, signedTx.Hash(), hash)
+	assert.Equal(t, txpool.TxStatusPending, node.txPool.GetTxStatus(hash, uint32(time.Now().Unix())).Status)
+
+	// lock again
+	assert.NoError(t, accAPI.Lock(address.String()))
+	_, err = accAPI.SignTx(testTx)
+	assert.Equal(t, keystore.ErrLocked, err)
+}
+
+func TestTxAPI_multisig(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	multisigAddr := common.HexToAddress("0x1234")
+	private1, _ := crypto.GenerateKey()
+	private2, _ := crypto.GenerateKey()
+	node := newTestNode(bc, db)
+	node.chainID = 100
+	node.txPool = txpool.NewTxPool()
+	node.multisig = txpool.NewMultisigPool(func(address common.Address) types.Signers {
+		return types.Signers{{Address: crypto.PubkeyToAddress(private1.PublicKey), Weight: 50}, {Address: crypto.PubkeyToAddress(private2.PublicKey), Weight: 50}}
+	}, func(tx *types.Transaction) error {
+		_, err := NewPublicTxAPI(node).SendTx(tx)
+		return err
+	})
+	txAPI := NewPublicTxAPI(node)
+
+	testTx := types.NewTransaction(multisigAddr, common.HexToAddress("0x1"), common.Big1, 100, big.NewInt(1000000000), []byte{12}, 0, 100, uint64(time.Now().Unix()+60*30), "aa", string("send a Tx"))
+	hash := types.MakeSigner().Hash(testTx)
+	sig2, err := crypto.Sign(hash[:], private2)
+	assert.NoError(t, err)
+
+	// invalid transaction
+	_, err = txAPI.ProposeMultisig(types.NewTransaction(multisigAddr, common.HexToAddress("0x1"), common.Big1, 100, big.NewInt(1000000000), nil, 0, 101, uint64(time.Now().Unix()+60*30), "", ""))
+	assert.Equal(t, types.ErrTxChainID, err)
+
+	// propose
+	record, err := txAPI.ProposeMultisig(testchain.SignTx(testTx, private1))
+	assert.NoError(t, err)
+	assert.Equal(t, hash, record.Hash)
+	pending, err := txAPI.GetPendingMultisig(multisigAddr.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pending))
+	_, err = txAPI.GetPendingMultisig("0x1")
+	assert.Error(t, err)
+
+	// reach the threshold
+	record, err = txAPI.AddSignature(hash, sig2)
+	assert.NoError(t, err)
+	assert.NotNil(t, record.TxHash)
+	status, err := txAPI.GetTxStatus(record.TxHash.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, txpool.TxStatusPending, status.Status)
+	pending, err = txAPI.GetPendingMultisig(multisigAddr.String())
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestTxAPI_GetTxStatus(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	from := crypto.PubkeyToAddress(testchain.FounderPrivate.PublicKey)
+	testTx := types.NewTransaction(from, common.HexToAddress("0x1"), common.Big1, 100, big.NewInt(1000000000), []byte{12}, 0, 100, uint64(time.Now().Unix()+60*30), "aa", string("send a Tx"))
+	tx := testchain.SignTx(testTx, testchain.FounderPrivate)
+	node := newTestNode(bc, db)
+	node.chainID = 100
+	node.txPool = txpool.NewTxPool()
+	txAPI := NewPublicTxAPI(node)
+
+	// invalid hash
+	_, err := txAPI.GetTxStatus("0x1234")
+	assert.Equal(t, ErrInputParams, err)
+
+	// not exist tx
+	status, err := txAPI.GetTxStatus(tx.Hash().Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, txpool.TxStatusUnknown, status.Status)
+
+	// pending tx
+	_, err = txAPI.SendTx(tx)
+	assert.NoError(t, err)
+	status, err = txAPI.GetTxStatus(tx.Hash().Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, txpool.TxStatusPending, status.Status)
+
+	// dropped tx
+	node.txPool.DelInvalidTxs(types.Transactions{tx}, []error{errors.New("insufficient balance")})
+	status, err = txAPI.GetTxStatus(tx.Hash().Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, &txpool.TxStatus{Status: txpool.TxStatusDropped, Reason: "insufficient balance"}, status)
+}
+
+// TestTxAPI_GetTxByHash query stable tx api test
+func TestTxAPI_GetTxByHash(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	txAPI := NewPublicTxAPI(node)
+
+	// invalid hash
+	_, err := txAPI.GetTxByHash("0x1234")
+	assert.Equal(t, ErrInputParams, err)
+
+	// not exist tx
+	txDetail, err := txAPI.GetTxByHash(common.HexToHash("0x1234").Hex())
+	assert.NoError(t, err)
+	assert.Nil(t, txDetail)
+
+	// tx list
+	txList, err := txAPI.GetTxListByAddress(testchain.FounderAddr.String(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), txList.Total)
+	assert.Len(t, txList.VTransactions, 0)
+	_, err = txAPI.GetTxListByAddress(testchain.FounderAddr.String(), 0, 1000)
+	assert.Error(t, err)
+	_, err = txAPI.GetTxListByAddress("0x015780F8456F9c1532645087a19DcF9a7e0c7F97", 0, 10)
+	assert.Equal(t, common.ErrInvalidAddress, err)
+
+	// internal tx list
+	internalTxList, err := txAPI.GetInternalTxListByAddress(testchain.FounderAddr.String(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), internalTxList.Total)
+	assert.Len(t, internalTxList.InternalTxs, 0)
+	_, err = txAPI.GetInternalTxListByAddress(testchain.FounderAddr.String(), 0, 1000)
+	assert.Error(t, err)
+}
+
+func TestTxAPI_GetReceipt(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	txAPI := NewPublicTxAPI(node)
+
+	// invalid hash
+	_, err := txAPI.GetReceipt("0x1234")
+	assert.Equal(t, ErrInputParams, err)
+
+	// not exist receipt
+	receipt, err := txAPI.GetReceipt(common.HexToHash("0x1234").Hex())
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+}
+
+func TestTxAPI_EstimateGas(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	txAPI := NewPublicTxAPI(node)
+
+	_, err := txAPI.EstimateGas(nil)
+	assert.Equal(t, ErrInputParams, err)
+
+	// the founder of test chain
+	founder := common.HexToAddress("0x10000")
+	tx := types.NewTransaction(founder, common.HexToAddress("0x99999"), big.NewInt(1), 0, big.NewInt(1), nil, params.OrdinaryTx, 200, uint64(time.Now().Unix()+300), "", "")
+	gas, err := txAPI.EstimateGas(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(params.OrdinaryTxGas), gas)
+
+	// no balance to pay gas
+	tx = types.NewTransaction(common.HexToAddress("0x99999"), founder, big.NewInt(0), 0, big.NewInt(1), nil, params.OrdinaryTx, 200, uint64(time.Now().Unix()+300), "", "")
+	_, err = txAPI.EstimateGas(tx)
+	assert.Error(t, err)
+}
+
+func TestTxAPI_ReadContractAt(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	txAPI := NewPublicTxAPI(node)
+
+	to := common.HexToAddress("0x99999")
+	_, err := txAPI.ReadContractAt(nil, nil, "0")
+	assert.Equal(t, ErrInputParams, err)
+	_, err = txAPI.ReadContractAt(&to, nil, "abc")
+	assert.Equal(t, ErrInputParams, err)
+	_, err = txAPI.ReadContractAt(&to, nil, "100")
+	assert.Equal(t, ErrBlockNotFound, err)
+	_, err = txAPI.ReadContractAt(&to, nil, common.HexToHash("0x1234").Hex())
+	assert.Equal(t, ErrBlockNotFound, err)
+
+	_, err = txAPI.ReadContractAt(&to, nil, "0x0")
+	assert.Equal(t, account.ErrHistoryUnavailable, err)
+
+	// no code in the account
+	result, err := txAPI.ReadContractAt(&to, nil, bc.StableBlock().Hash().Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, "0x0", result)
+	result, err = txAPI.ReadContractAt(&to, nil, bc.CurrentBlock().Hash().Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, "0x0", result)
+}
+
+func TestAccountHistoryAPI(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	historyAPI := NewPublicAccountHistoryAPI(node)
+
+	_, err := historyAPI.GetAccountAt("0x015780F8456F9c1532645087a19DcF9a7e0c7F97", 0)
+	assert.Equal(t, common.ErrInvalidAddress, err)
+	_, err = historyAPI.GetAccountAt(testchain.FounderAddr.String(), 100)
+	assert.Equal(t, ErrBlockNotFound, err)
+
+	// genesis is older than the stable block
+	_, err = historyAPI.GetAccountAt(testchain.FounderAddr.String(), 0)
+	assert.Equal(t, account.ErrHistoryUnavailable, err)
+	_, err = historyAPI.GetProof(testchain.FounderAddr.String(), nil, bc.Genesis().Hash())
+	assert.Equal(t, account.ErrHistoryUnavailable, err)
+
+	acc, err := historyAPI.GetAccountAt(testchain.FounderAddr.String(), bc.StableBlock().Height())
+	assert.NoError(t, err)
+	assert.Equal(t, bc.AccountManager().GetCanonicalAccount(testchain.FounderAddr).GetBalance(), acc.GetBalance())
+
+	// proof
+	_, err = historyAPI.GetProof(testchain.FounderAddr.String(), nil, common.HexToHash("0x1234"))
+	assert.Equal(t, ErrBlockNotFound, err)
+	current := bc.CurrentBlock()
+	proof, err := historyAPI.GetProof(testchain.FounderAddr.String(), []common.Hash{common.HexToHash("0x1")}, current.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, testchain.FounderAddr, proof.Account.Address)
+	assert.NotEmpty(t, proof.VersionProofs)
+	assert.Len(t, proof.StorageProofs, 1)
+	loadLogRoot := func(height uint32) (common.Hash, error) {
+		block := bc.GetParentByHeight(height, current.Hash())
+		if block == nil {
+			return common.Hash{}, ErrBlockNotFound
+		}
+		return block.LogRoot(), nil
+	}
+	assert.NoError(t, account.VerifyAccountProof(current.VersionRoot(), proof, loadLogRoot))
+	proof.Account.Balance = new(big.Int).Add(proof.Account.Balance, big.NewInt(1))
+	assert.Equal(t, account.ErrInvalidProof, account.VerifyAccountProof(current.VersionRoot(), proof, loadLogRoot))
+}
+
+func TestProofAPI(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	proofAPI := NewPublicProofAPI(node)
+
+	_, err := proofAPI.GetTxProof(common.HexToHash("0x1234"))
+	assert.Equal(t, ErrTxNotFound, err)
+
+	block := bc.GetBlockByHeight(0)
+	assert.NotEmpty(t, block.ChangeLogs)
+	cl := block.ChangeLogs[len(block.ChangeLogs)-1]
+	proof, err := proofAPI.GetChangeLogProof(block.Hash(), cl.Address.String(), uint32(cl.LogType), cl.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, cl, proof.ChangeLog)
+	assert.Equal(t, block.Height(), proof.Height)
+	assert.True(t, merkle.Verify(proof.ChangeLog.Hash(), block.LogRoot(), proof.Proof))
+
+	_, err = proofAPI.GetChangeLogProof(block.Hash(), cl.Address.String(), uint32(cl.LogType), cl.Version+100)
+	assert.Equal(t, ErrChangeLogNotFound, err)
+	_, err = proofAPI.GetChangeLogProof(common.HexToHash("0x1234"), cl.Address.String(), uint32(cl.LogType), cl.Version)
+	assert.Equal(t, ErrBlockNotFound, err)
+	_, err = proofAPI.GetChangeLogProof(block.Hash(), "0x015780F8456F9c1532645087a19DcF9a7e0c7F97", uint32(cl.LogType), cl.Version)
+	assert.Equal(t, common.ErrInvalidAddress, err)
+}
+
+func TestAssetAPI(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	assetAPI := NewPublicAssetAPI(node)
+
+	_, err := assetAPI.GetAsset(common.HexToHash("0x1234"))
+	assert.Equal(t, ErrAssetNotFound, err)
+	_, err = assetAPI.GetTotalSupply(common.HexToHash("0x1234"))
+	assert.Equal(t, ErrAssetNotFound, err)
+	_, err = assetAPI.GetMetaData(common.HexToHash("0x1234"))
+	assert.Equal(t, ErrAssetIdNotFound, err)
+
+	_, err = assetAPI.GetAssetsByIssuer("0x015780F8456F9c1532645087a19DcF9a7e0c7F97")
+	assert.Equal(t, common.ErrInvalidAddress, err)
+	assets, err := assetAPI.GetAssetsByIssuer(testchain.FounderAddr.String())
+	assert.NoError(t, err)
+	assert.Empty(t, assets)
+
+	_, err = assetAPI.GetEquitiesByOwner(testchain.FounderAddr.String(), -1, 10)
+	assert.Equal(t, store.ErrArgInvalid, err)
+	_, err = assetAPI.GetEquitiesByOwner(testchain.FounderAddr.String(), 0, 201)
+	assert.Equal(t, store.ErrArgInvalid, err)
+	equities, err := assetAPI.GetEquitiesByOwner(testchain.FounderAddr.String(), 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, equities.Equities)
+	assert.Equal(t, uint32(0), equities.Total)
+
+	// holders and transfers
+	_, err = assetAPI.GetHolders(common.HexToHash("0x1234"), 0, 0)
+	assert.Equal(t, store.ErrArgInvalid, err)
+	holders, err := assetAPI.GetHolders(common.HexToHash("0x1234"), 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, holders.Holders)
+	assert.Equal(t, uint32(0), holders.Total)
+	_, err = assetAPI.GetTransfers(common.HexToHash("0x1234"), -1, 10)
+	assert.Equal(t, store.ErrArgInvalid, err)
+	transfers, err := assetAPI.GetTransfers(common.HexToHash("0x1234"), 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, transfers.VTransactions)
+	assert.Equal(t, uint32(0), transfers.Total)
+}
+
+func TestDebugAPI(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	debugAPI := NewPrivateDebugAPI(node)
+
+	_, err := debugAPI.TraceTransaction(common.HexToHash("0x1234"), nil)
+	assert.Equal(t, ErrTxNotFound, err)
+
+	to := common.HexToAddress("0x99999")
+	call := CallArgs{From: common.HexToAddress("0x10000"), To: &to, Value: (*hexutil.Big10)(big.NewInt(1))}
+	_, err = debugAPI.TraceCall(call, common.HexToHash("0x1234"), nil)
+	assert.Equal(t, ErrBlockNotFound, err)
+	_, err = debugAPI.TraceCall(call, bc.CurrentBlock().Hash(), &TraceConfig{Tracer: "abc"})
+	assert.Equal(t, ErrUnknownTracer, err)
+
+	// no code in the account
+	result, err := debugAPI.TraceCall(call, bc.CurrentBlock().Hash(), nil)
+	assert.NoError(t, err)
+	assert.False(t, result.(*ExecutionResult).Failed)
+	assert.Empty(t, result.(*ExecutionResult).StructLogs)
+
+	// call tree
+	result, err = debugAPI.TraceCall(call, bc.CurrentBlock().Hash(), &TraceConfig{Tracer: callTracerName})
+	assert.NoError(t, err)
+	frame := result.(*vm.CallFrame)
+	assert.Equal(t, "CALL", frame.Type)
+	assert.Equal(t, to, frame.To)
+	assert.Equal(t, big.NewInt(1), frame.Value)
+	assert.Empty(t, frame.Calls)
+}
+
+func TestFilterAPI(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	filterAPI := NewPublicFilterAPI(node)
+	stableHeight := bc.StableBlock().Height()
+
+	// query events
+	events, err := filterAPI.GetEvents(EventQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+	_, err = filterAPI.GetEvents(EventQuery{FromHeight: stableHeight + 1})
+	assert.Equal(t, ErrEventsQueryRange, err)
+
+	// filter
+	_, err = filterAPI.NewFilter(EventQuery{FromHeight: 2, ToHeight: 1})
+	assert.Equal(t, ErrEventsQueryRange, err)
+	id, err := filterAPI.NewFilter(EventQuery{Addresses: []common.Address{testchain.FounderAddr}})
+	assert.NoError(t, err)
+	events, err = filterAPI.GetFilterChanges(id)
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+	assert.Equal(t, stableHeight, filterAPI.filters[id].lastHeight)
+
+	// filter from future block
+	futureID, err := filterAPI.NewFilter(EventQuery{FromHeight: stableHeight + 10})
+	assert.NoError(t, err)
+	events, err = filterAPI.GetFilterChanges(futureID)
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+	assert.Equal(t, stableHeight+9, filterAPI.filters[futureID].lastHeight)
+
+	// expired filter
+	filterAPI.filters[id].deadline = time.Now().Add(-time.Second)
+	_, err = filterAPI.GetFilterChanges(id)
+	assert.Equal(t, ErrFilterNotFound, err)
+
+	// uninstall
+	id, err = filterAPI.NewFilter(EventQuery{})
+	assert.NoError(t, err)
+	assert.True(t, filterAPI.UninstallFilter(id))
+	assert.False(t, filterAPI.UninstallFilter(id))
+	_, err = filterAPI.GetFilterChanges(id)
+	assert.Equal(t, ErrFilterNotFound, err)
+}
+
+func TestSubscribeAPI(t *testing.T) {
+	bc, db := testchain.NewTestChain()
+	defer testchain.CloseTestChain(bc, db)
+
+	node := newTestNode(bc, db)
+	subscribeAPI := NewPublicSubscribeAPI(node)
+
+	// connection without notifier
+	_, err := subscribeAPI.NewBlocks(context.Background())
+	assert.Equal(t, rpc.ErrNotificationsUnsupported, err)
+
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("chain", subscribeAPI))
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	// unknown event name
+	_, err = client.Subscribe(context.Background(), "chain", make(chan *types.Block), "unknown")
+	assert.Error(t, err)
+
+	// pending txs
+	txCh := make(chan *types.Transaction, 10)
+	sub, err := client.Subscribe(context.Background(), "chain", txCh, "pendingTxs")
+	assert.NoError(t, err)
+	tx := types.NewTransaction(testchain.FounderAddr, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil, params.OrdinaryTx, 200, uint64(time.Now().Unix()+300), "", "")
+	// the subscription is activated after the subscription id is sent, so keep sending until the tx is received
+	timeout := time.After(5 * time.Second)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for received := false; !received; {
+		select {
+		case <-ticker.C:
+			go subscribe.Send(subscribe.NewTx, tx)
+		case result := <-txCh:
+			assert.Equal(t, tx.Hash(), result.Hash())
+			received = true
+		case <-timeout:
+			t.Fatal("receive pending tx timeout")
+		}
+	}
+	sub.Unsubscribe()
+
+	// stable blocks and events
+	_, err = client.Subscribe(context.Background(), "chain", make(chan *types.Block), "newStableBlocks")
+	assert.NoError(t, err)
+	_, err = client.Subscribe(context.Background(), "chain", make(chan *store.VEvent), "events", EventQuery{Addresses: []common.Address{testchain.FounderAddr}})
+	assert.NoError(t, err)
+}
+
 // 序列化注册候选节点所用data
 func Test_CreatRegisterTxData(t *testing.T) {
 	pro1 := make(types.Profile)
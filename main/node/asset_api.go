@@ -0,0 +1,134 @@
+package node
+
+import (
+	"errors"
+
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+)
+
+var (
+	ErrAssetNotFound   = errors.New("the asset is not found")
+	ErrAssetIdNotFound = errors.New("the asset id is not found")
+)
+
+//go:generate gencodec -type EquityListRes --field-override equityListResMarshaling -out gen_equity_list_res_json.go
+type EquityListRes struct {
+	Equities []*types.AssetEquity `json:"equities" gencodec:"required"`
+	Total    uint32               `json:"total" gencodec:"required"`
+}
+
+type equityListResMarshaling struct {
+	Total hexutil.Uint32
+}
+
+//go:generate gencodec -type AssetHolderListRes --field-override assetHolderListResMarshaling -out gen_asset_holder_list_res_json.go
+type AssetHolderListRes struct {
+	Holders []*store.VAssetHolder `json:"holders" gencodec:"required"`
+	Total   uint32                `json:"total" gencodec:"required"`
+}
+
+type assetHolderListResMarshaling struct {
+	Total hexutil.Uint32
+}
+
+// PublicAssetAPI API for access to assets, equities and the metadata of assets on stable chain
+type PublicAssetAPI struct {
+	node *Node
+}
+
+// NewPublicAssetAPI
+func NewPublicAssetAPI(node *Node) *PublicAssetAPI {
+	return &PublicAssetAPI{node}
+}
+
+// GetAsset returns the asset information. The issuer is found by asset code
+func (a *PublicAssetAPI) GetAsset(assetCode common.Hash) (*types.Asset, error) {
+	issuer, err := a.node.db.GetAssetCode(assetCode)
+	if err == store.ErrNotExist || (err == nil && issuer == (common.Address{})) {
+		return nil, ErrAssetNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return a.node.chain.AccountManager().GetCanonicalAccount(issuer).GetAssetCode(assetCode)
+}
+
+// GetAssetsByIssuer returns all assets created by the issuer
+func (a *PublicAssetAPI) GetAssetsByIssuer(lemoAddress string) ([]*types.Asset, error) {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	return a.node.chain.AccountManager().GetCanonicalAccount(address).GetAssetCodes()
+}
+
+// GetEquitiesByOwner returns the asset equities owned by the address by page. The equities are sorted by asset id
+func (a *PublicAssetAPI) GetEquitiesByOwner(lemoAddress string, index int, size int) (*EquityListRes, error) {
+	if (index < 0) || (size > 200) || (size <= 0) {
+		return nil, store.ErrArgInvalid
+	}
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	equities, err := a.node.chain.AccountManager().GetCanonicalAccount(address).GetEquities()
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(equities)
+	if index >= total {
+		return &EquityListRes{Equities: make([]*types.AssetEquity, 0), Total: uint32(total)}, nil
+	}
+	end := index + size
+	if end > total {
+		end = total
+	}
+	return &EquityListRes{Equities: equities[index:end], Total: uint32(total)}, nil
+}
+
+// GetMetaData returns the metadata of the asset id which is set by the issuer
+func (a *PublicAssetAPI) GetMetaData(assetId common.Hash) (string, error) {
+	issuer, err := a.node.db.GetAssetID(assetId)
+	if err == store.ErrNotExist || (err == nil && issuer == (common.Address{})) {
+		return "", ErrAssetIdNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return a.node.chain.AccountManager().GetCanonicalAccount(issuer).GetAssetIdState(assetId)
+}
+
+// GetTotalSupply returns the total supply of the asset
+func (a *PublicAssetAPI) GetTotalSupply(assetCode common.Hash) (string, error) {
+	asset, err := a.GetAsset(assetCode)
+	if err != nil {
+		return "", err
+	}
+	return asset.TotalSupply.String(), nil
+}
+
+// GetHolders returns the accounts which hold the asset in stable blocks by page. The holders are sorted by address and asset id
+func (a *PublicAssetAPI) GetHolders(assetCode common.Hash, index int, size int) (*AssetHolderListRes, error) {
+	holders, total, err := a.node.db.GetBizDatabase().GetAssetHolders(assetCode, index, size)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetHolderListRes{
+		Holders: holders,
+		Total:   total,
+	}, nil
+}
+
+// GetTransfers returns the transactions which issue, replenish or transfer the asset id in stable blocks by page
+func (a *PublicAssetAPI) GetTransfers(assetId common.Hash, index int, size int) (*TxListRes, error) {
+	txs, total, err := a.node.db.GetBizDatabase().GetAssetTransfers(assetId, index, size)
+	if err != nil {
+		return nil, err
+	}
+	return &TxListRes{
+		VTransactions: txs,
+		Total:         total,
+	}, nil
+}
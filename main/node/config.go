@@ -27,6 +27,8 @@ const (
 	datadirStaticNodes  = "static-nodes.json"
 	datadirTrustedNodes = "trusted-nodes.json"
 	datadirNodeDatabase = "nodes"
+	datadirTxJournal    = "transactions.rlp"
+	datadirKeystore     = "keystore"
 )
 
 var DefaultHTTPVirtualHosts = []string{"localhost"}
@@ -0,0 +1,201 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/account"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/vm"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+	"math/big"
+)
+
+const (
+	// defaultTraceGasLimit is the gas limit of traced call if it is not set
+	defaultTraceGasLimit = uint64(50000000)
+	// callTracerName is the name of tracer which records the tree of calls made by contracts
+	callTracerName = "callTracer"
+)
+
+var (
+	ErrTxNotFound    = errors.New("the transaction is not found in stable blocks")
+	ErrBlockNotFound = errors.New("the block is not found")
+	ErrUnknownTracer = errors.New("unknown tracer")
+)
+
+// TraceConfig is the config of tracing. The vm steps are traced if Tracer is empty, and the call tree is traced if Tracer is "callTracer"
+type TraceConfig struct {
+	*vm.LogConfig
+	Tracer string `json:"tracer"`
+}
+
+// CallArgs is the message call which is traced by debug_traceCall
+type CallArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"` // nil means contract creation
+	Gas   hexutil.Uint64  `json:"gas"`
+	Value *hexutil.Big10  `json:"value"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+// ExecutionResult groups all structured logs emitted by the EVM
+// while replaying a transaction in debug mode
+type ExecutionResult struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	VmErr       string         `json:"vmErr"`
+	ReturnValue string         `json:"returnValue"`
+	StructLogs  []StructLogRes `json:"structLogs"`
+}
+
+// StructLogRes stores a structured log emitted by the EVM while replaying a
+// transaction in debug mode
+type StructLogRes struct {
+	Pc      uint64             `json:"pc"`
+	Op      string             `json:"op"`
+	Gas     uint64             `json:"gas"`
+	GasCost uint64             `json:"gasCost"`
+	Depth   int                `json:"depth"`
+	Error   string             `json:"error,omitempty"`
+	Stack   *[]string          `json:"stack,omitempty"`
+	Memory  *[]string          `json:"memory,omitempty"`
+	Storage *map[string]string `json:"storage,omitempty"`
+}
+
+// PrivateDebugAPI API for re-executing transactions with vm tracer
+type PrivateDebugAPI struct {
+	node *Node
+}
+
+// NewPrivateDebugAPI
+func NewPrivateDebugAPI(node *Node) *PrivateDebugAPI {
+	return &PrivateDebugAPI{node}
+}
+
+// TraceTransaction re-executes a stable transaction on top of its parent block state, and returns the vm steps or call tree of it
+func (d *PrivateDebugAPI) TraceTransaction(txHash common.Hash, config *TraceConfig) (interface{}, error) {
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := d.node.db.GetBizDatabase().GetTxByHash(txHash)
+	if err == store.ErrNotExist {
+		return nil, ErrTxNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	block := d.node.chain.GetBlockByHash(detail.BlockHash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+
+	receipt, err := d.node.chain.TxProcessor().TraceTx(block, txHash, tracer)
+	if err != nil {
+		return nil, err
+	}
+	if callTracer, ok := tracer.(*vm.CallTracer); ok {
+		return callTracer.Result(), nil
+	}
+	structLogger := tracer.(*vm.StructLogger)
+	return &ExecutionResult{
+		Gas:         receipt.GasUsed,
+		Failed:      receipt.Status == types.ReceiptStatusFailed,
+		VmErr:       receipt.VmErr,
+		ReturnValue: fmt.Sprintf("%x", structLogger.Output()),
+		StructLogs:  formatLogs(structLogger.StructLogs()),
+	}, nil
+}
+
+// TraceCall executes a message call on the state of the block, and returns the vm steps or call tree of it. The chain state is not changed
+func (d *PrivateDebugAPI) TraceCall(call CallArgs, blockHash common.Hash, config *TraceConfig) (interface{}, error) {
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	block := d.node.chain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	gasLimit := uint64(call.Gas)
+	if gasLimit == 0 {
+		gasLimit = defaultTraceGasLimit
+	}
+	value := new(big.Int)
+	if call.Value != nil {
+		value = (*big.Int)(call.Value)
+	}
+
+	// the state of old stable block is rebuilt for debugging
+	accM := account.NewHistoryReadOnlyManager(d.node.db)
+	ret, gasUsed, vmErr, err := d.node.chain.TxProcessor().TraceCall(accM, block.Header, call.From, call.To, call.Data, value, gasLimit, tracer)
+	if err != nil {
+		return nil, err
+	}
+	if callTracer, ok := tracer.(*vm.CallTracer); ok {
+		return callTracer.Result(), nil
+	}
+	result := &ExecutionResult{
+		Gas:         gasUsed,
+		Failed:      vmErr != nil,
+		ReturnValue: fmt.Sprintf("%x", ret),
+		StructLogs:  formatLogs(tracer.(*vm.StructLogger).StructLogs()),
+	}
+	if vmErr != nil {
+		result.VmErr = vmErr.Error()
+	}
+	return result, nil
+}
+
+// newTracer creates the tracer specified by config
+func newTracer(config *TraceConfig) (vm.Tracer, error) {
+	if config == nil {
+		return vm.NewStructLogger(nil), nil
+	}
+	switch config.Tracer {
+	case "":
+		return vm.NewStructLogger(config.LogConfig), nil
+	case callTracerName:
+		return vm.NewCallTracer(), nil
+	default:
+		return nil, ErrUnknownTracer
+	}
+}
+
+// formatLogs formats EVM returned structured logs for json output
+func formatLogs(logs []vm.StructLog) []StructLogRes {
+	formatted := make([]StructLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = StructLogRes{
+			Pc:      trace.Pc,
+			Op:      trace.Op.String(),
+			Gas:     trace.Gas,
+			GasCost: trace.GasCost,
+			Depth:   trace.Depth,
+			Error:   trace.ErrorString(),
+		}
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, stackValue := range trace.Stack {
+				stack[i] = fmt.Sprintf("%x", common.LeftPadBytes(stackValue.Bytes(), 32))
+			}
+			formatted[index].Stack = &stack
+		}
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
+			}
+			formatted[index].Memory = &memory
+		}
+		if trace.Storage != nil {
+			storage := make(map[string]string)
+			for i, storageValue := range trace.Storage {
+				storage[fmt.Sprintf("%x", i)] = fmt.Sprintf("%x", storageValue)
+			}
+			formatted[index].Storage = &storage
+		}
+	}
+	return formatted
+}
@@ -0,0 +1,139 @@
+package node
+
+import (
+	"errors"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/network/rpc"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+	"sync"
+	"time"
+)
+
+// filterTimeout is the time a filter is kept if there is no getFilterChanges request
+const filterTimeout = 5 * time.Minute
+
+var (
+	ErrFilterNotFound   = errors.New("filter not found")
+	ErrEventsQueryRange = errors.New("the height range of events query is invalid or too large")
+)
+
+// EventQuery is the condition to search contract events in stable blocks
+type EventQuery struct {
+	FromHeight uint32 `json:"fromHeight"`
+	// ToHeight is the current stable height if it is 0
+	ToHeight uint32 `json:"toHeight"`
+	// The events must be sent by one of Addresses. Empty means any address
+	Addresses []common.Address `json:"address"`
+	// The topic[i] of event must be one of Topics[i]. Empty or null Topics[i] means any topic
+	Topics [][]common.Hash `json:"topics"`
+}
+
+// eventFilter is a stateful filter which records the position of the last query
+type eventFilter struct {
+	query      EventQuery
+	lastHeight uint32 // the last height which has been searched
+	deadline   time.Time
+}
+
+// PublicFilterAPI API for searching contract events
+type PublicFilterAPI struct {
+	node    *Node
+	filters map[rpc.ID]*eventFilter
+	lock    sync.Mutex
+}
+
+// NewPublicFilterAPI
+func NewPublicFilterAPI(node *Node) *PublicFilterAPI {
+	return &PublicFilterAPI{
+		node:    node,
+		filters: make(map[rpc.ID]*eventFilter),
+	}
+}
+
+// GetEvents search the events in stable blocks. The height range can't be larger than store.MaxEventsQueryRange
+func (f *PublicFilterAPI) GetEvents(query EventQuery) ([]*store.VEvent, error) {
+	stableHeight := f.node.chain.StableBlock().Height()
+	toHeight := query.ToHeight
+	if toHeight == 0 || toHeight > stableHeight {
+		toHeight = stableHeight
+	}
+	if query.FromHeight > toHeight || toHeight-query.FromHeight >= store.MaxEventsQueryRange {
+		return nil, ErrEventsQueryRange
+	}
+	return f.node.db.GetBizDatabase().GetEvents(query.FromHeight, toHeight, query.Addresses, query.Topics)
+}
+
+// NewFilter creates a filter and returns its id. Then call GetFilterChanges to poll the new events since last poll.
+// The filter will be removed if it is not polled within 5 minutes
+func (f *PublicFilterAPI) NewFilter(query EventQuery) (rpc.ID, error) {
+	stableHeight := f.node.chain.StableBlock().Height()
+	if query.ToHeight != 0 && query.FromHeight > query.ToHeight {
+		return "", ErrEventsQueryRange
+	}
+	// search from current stable block by default. The future blocks are waited until they become stable
+	lastHeight := stableHeight
+	if query.FromHeight > 0 {
+		lastHeight = query.FromHeight - 1
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.removeExpiredFilters()
+	id := rpc.NewID()
+	f.filters[id] = &eventFilter{
+		query:      query,
+		lastHeight: lastHeight,
+		deadline:   time.Now().Add(filterTimeout),
+	}
+	return id, nil
+}
+
+// GetFilterChanges returns the new events since last poll. It searches store.MaxEventsQueryRange blocks at most in one time
+func (f *PublicFilterAPI) GetFilterChanges(id rpc.ID) ([]*store.VEvent, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.removeExpiredFilters()
+	filter, ok := f.filters[id]
+	if !ok {
+		return nil, ErrFilterNotFound
+	}
+	filter.deadline = time.Now().Add(filterTimeout)
+
+	fromHeight := filter.lastHeight + 1
+	toHeight := f.node.chain.StableBlock().Height()
+	if filter.query.ToHeight != 0 && filter.query.ToHeight < toHeight {
+		toHeight = filter.query.ToHeight
+	}
+	if fromHeight > toHeight {
+		return make([]*store.VEvent, 0), nil
+	}
+	if toHeight-fromHeight >= store.MaxEventsQueryRange {
+		toHeight = fromHeight + store.MaxEventsQueryRange - 1
+	}
+
+	events, err := f.node.db.GetBizDatabase().GetEvents(fromHeight, toHeight, filter.query.Addresses, filter.query.Topics)
+	if err != nil {
+		return nil, err
+	}
+	filter.lastHeight = toHeight
+	return events, nil
+}
+
+// UninstallFilter removes the filter. It returns false if the filter is not exist
+func (f *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, ok := f.filters[id]
+	delete(f.filters, id)
+	return ok
+}
+
+// removeExpiredFilters removes the filters which are not polled for a long time
+func (f *PublicFilterAPI) removeExpiredFilters() {
+	now := time.Now()
+	for id, filter := range f.filters {
+		if now.After(filter.deadline) {
+			delete(f.filters, id)
+		}
+	}
+}
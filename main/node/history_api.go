@@ -0,0 +1,79 @@
+package node
+
+import (
+	"github.com/LemoFoundationLtd/lemochain-core/chain/account"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"strconv"
+	"strings"
+)
+
+// PublicAccountHistoryAPI API for access to account information at historical block
+type PublicAccountHistoryAPI struct {
+	node *Node
+}
+
+// NewPublicAccountHistoryAPI
+func NewPublicAccountHistoryAPI(node *Node) *PublicAccountHistoryAPI {
+	return &PublicAccountHistoryAPI{node}
+}
+
+// GetAccountAt returns the account on the state of the block at height. The unstable block is in current fork.
+func (a *PublicAccountHistoryAPI) GetAccountAt(lemoAddress string, height uint32) (types.AccountAccessor, error) {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	block := a.node.chain.GetBlockByHeight(height)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+
+	a.node.lock.Lock()
+	defer a.node.lock.Unlock()
+	accM := account.NewReadOnlyManager(a.node.db, false)
+	if err := accM.Reset(block.Hash()); err != nil {
+		return nil, err
+	}
+	return accM.GetAccount(address), nil
+}
+
+// GetProof returns the merkle proofs of the account versions and storage values on the state of the block.
+// The versions are proven by the VersionRoot in block header, the account fields are proven by the newest change logs and the LogRoot of the blocks which contain them,
+// and the storage values are proven by the StorageRoot in account.
+func (a *PublicAccountHistoryAPI) GetProof(lemoAddress string, storageKeys []common.Hash, blockHash common.Hash) (*account.AccountProof, error) {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	block := a.node.chain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+
+	a.node.lock.Lock()
+	defer a.node.lock.Unlock()
+	accM := account.NewReadOnlyManager(a.node.db, false)
+	if err := accM.Reset(block.Hash()); err != nil {
+		return nil, err
+	}
+	return accM.GetProof(address, storageKeys)
+}
+
+// blockByHashOrHeight finds the block by a 32 bytes hex hash, or a decimal or hex height
+func (n *Node) blockByHashOrHeight(hashOrHeight string) (*types.Block, error) {
+	var block *types.Block
+	if strings.HasPrefix(hashOrHeight, "0x") && len(hashOrHeight) == 2+2*common.HashLength {
+		block = n.chain.GetBlockByHash(common.HexToHash(hashOrHeight))
+	} else {
+		height, err := strconv.ParseUint(hashOrHeight, 0, 32)
+		if err != nil {
+			return nil, ErrInputParams
+		}
+		block = n.chain.GetBlockByHeight(uint32(height))
+	}
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	return block, nil
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/txpool"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
 	"github.com/LemoFoundationLtd/lemochain-core/common/flag"
 	"github.com/LemoFoundationLtd/lemochain-core/common/flock"
+	"github.com/LemoFoundationLtd/lemochain-core/common/keystore"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/main/config"
 	"github.com/LemoFoundationLtd/lemochain-core/network"
@@ -22,6 +24,7 @@ import (
 	"github.com/inconshreveable/log15"
 	"math/big"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -36,6 +39,8 @@ type Node struct {
 	db       protocol.ChainDB
 	accMan   *account.Manager
 	txPool   *txpool.TxPool
+	multisig *txpool.MultisigPool
+	keystore *keystore.KeyStore
 	chain    *chain.BlockChain
 	pm       *network.ProtocolManager
 	miner    *miner.Miner
@@ -102,6 +107,11 @@ func GetChainDataPath(dataDir string) string {
 	return filepath.Join(dataDir, "chaindata")
 }
 
+// GetKeystorePath returns the directory of encrypted key files
+func GetKeystorePath(dataDir string) string {
+	return filepath.Join(dataDir, datadirKeystore)
+}
+
 func initDb(dataDir string) protocol.ChainDB {
 	dir := GetChainDataPath(dataDir)
 	return store.NewChainDataBase(dir)
@@ -133,10 +143,20 @@ func New(flags flag.CmdFlags) *Node {
 	dm := deputynode.NewManager(int(configFromFile.DeputyCount), db)
 	// tx pool
 	txPool := txpool.NewTxPool()
+	txPool.GasPricePriority = configFromFile.GasPricePriority
+	if configFromFile.TxPoolLimit > 0 {
+		txPool.GlobalLimit = int(configFromFile.TxPoolLimit)
+	}
+	if configFromFile.TxPoolSenderLimit > 0 {
+		txPool.SenderLimit = int(configFromFile.TxPoolSenderLimit)
+	}
 	blockChain, err := chain.NewBlockChain(cfg.Chain, dm, db, flags, txPool)
 	if err != nil {
 		panic("new block chain failed!!!")
 	}
+	if err := txPool.LoadJournal(filepath.Join(cfg.DataDir, datadirTxJournal), uint16(configFromFile.ChainID)); err != nil {
+		log.Errorf("Load tx pool journal failed: %v", err)
+	}
 	// discover manager
 	discover := p2p.NewDiscoverManager(cfg.DataDir)
 	// protocol manager
@@ -156,11 +176,19 @@ func New(flags flag.CmdFlags) *Node {
 		accMan:       blockChain.AccountManager(),
 		chain:        blockChain,
 		txPool:       txPool,
+		keystore:     keystore.NewKeyStore(GetKeystorePath(cfg.DataDir), keystore.StandardScryptN, keystore.StandardScryptP),
 		miner:        miner.New(cfg.Miner, blockChain, dm, txPool),
 		pm:           pm,
 		server:       server,
 		genesisBlock: genesisBlock,
 	}
+	// the multisig transaction is sent to tx pool once its signers' weight reaches the threshold
+	n.multisig = txpool.NewMultisigPool(func(address common.Address) types.Signers {
+		return n.accMan.GetCanonicalAccount(address).GetSigners()
+	}, func(tx *types.Transaction) error {
+		_, err := NewPublicTxAPI(n).SendTx(tx)
+		return err
+	})
 	return n
 }
 
@@ -219,7 +247,7 @@ func (n *Node) startRPC() error {
 		n.stopInProc()
 		return err
 	}
-	if err := n.startWS(apis); err != nil {
+	if err := n.startWS(n.wsEndpoint, apis, n.config.WSOrigins, n.config.WSExposeAll); err != nil {
 		n.stopHTTP()
 		n.stopIPC()
 		n.stopInProc()
@@ -350,12 +378,51 @@ func (n *Node) stopHTTP() {
 	}
 }
 
-func (n *Node) startWS(apis []rpc.API) error {
+func (n *Node) startWS(endpoint string, apis []rpc.API, wsOrigins []string, exposeAll bool) error {
+	// Short circuit if the WS endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	// Register all the APIs exposed by the services
+	handler := rpc.NewServer()
+	for _, api := range apis {
+		if exposeAll || api.Public {
+			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+				return err
+			}
+		}
+	}
+	// All APIs registered, start the WS listener
+	var (
+		listener net.Listener
+		err      error
+	)
+	if listener, err = net.Listen("tcp", endpoint); err != nil {
+		return err
+	}
+	go (&http.Server{Handler: handler.WebsocketHandler(wsOrigins)}).Serve(listener)
+	log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", endpoint), "origins", strings.Join(wsOrigins, ","))
+	// All listeners booted successfully
+	n.wsEndpoint = endpoint
+	n.wsListener = listener
+	n.wsHandler = handler
+
 	return nil
 }
 
 func (n *Node) stopWS() {
+	if n.wsListener != nil {
+		if err := n.wsListener.Close(); err != nil {
+			log.Errorf("close wsListener failed: %v", err)
+		}
+		n.wsListener = nil
 
+		log.Info("WebSocket endpoint closed", "url", fmt.Sprintf("ws://%s", n.wsEndpoint))
+	}
+	if n.wsHandler != nil {
+		n.wsHandler.Stop()
+		n.wsHandler = nil
+	}
 }
 
 func (n *Node) stopRPC() {
@@ -406,7 +473,7 @@ func (n *Node) Stop() error {
 func (n *Node) stopChain() error {
 	n.chain.Stop()
 	n.pm.Stop()
-	// n.txPool.Stop()
+	n.txPool.Stop()
 	n.miner.Close()
 	if err := n.db.Close(); err != nil {
 		return err
@@ -475,6 +542,24 @@ func (n *Node) apis() []rpc.API {
 			Service:   NewPublicChainAPI(n.chain),
 			Public:    true,
 		},
+		{
+			Namespace: "chain",
+			Version:   "1.0",
+			Service:   NewPublicFilterAPI(n),
+			Public:    true,
+		},
+		{
+			Namespace: "chain",
+			Version:   "1.0",
+			Service:   NewPublicSubscribeAPI(n),
+			Public:    true,
+		},
+		{
+			Namespace: "chain",
+			Version:   "1.0",
+			Service:   NewPublicProofAPI(n),
+			Public:    true,
+		},
 		{
 			Namespace: "mine",
 			Version:   "1.0",
@@ -496,7 +581,19 @@ func (n *Node) apis() []rpc.API {
 		{
 			Namespace: "account",
 			Version:   "1.0",
-			Service:   NewPrivateAccountAPI(n.accMan),
+			Service:   NewPublicAccountHistoryAPI(n),
+			Public:    true,
+		},
+		{
+			Namespace: "asset",
+			Version:   "1.0",
+			Service:   NewPublicAssetAPI(n),
+			Public:    true,
+		},
+		{
+			Namespace: "account",
+			Version:   "1.0",
+			Service:   NewPrivateAccountAPI(n.accMan, n.keystore),
 			Public:    false,
 		},
 		{
@@ -517,6 +614,18 @@ func (n *Node) apis() []rpc.API {
 			Service:   NewPublicTxAPI(n),
 			Public:    true,
 		},
+		{
+			Namespace: "tx",
+			Version:   "1.0",
+			Service:   NewPrivateTxAPI(n),
+			Public:    false,
+		},
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(n),
+			Public:    false,
+		},
 	}
 }
 
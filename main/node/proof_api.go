@@ -0,0 +1,128 @@
+package node
+
+import (
+	"errors"
+
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/common/merkle"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+)
+
+var (
+	ErrChangeLogNotFound = errors.New("the change log is not found in block")
+)
+
+//go:generate gencodec -type TxProof --field-override txProofMarshaling -out gen_tx_proof_json.go
+
+// TxProof proves the transaction is in block by the TxRoot in block header.
+// If the transaction is packed in a box transaction, BoxTx is the leaf of merkle trie instead of Tx
+type TxProof struct {
+	Tx        *types.Transaction  `json:"tx" gencodec:"required"`
+	BoxTx     *types.Transaction  `json:"boxTx"`
+	BlockHash common.Hash         `json:"blockHash" gencodec:"required"`
+	Height    uint32              `json:"height" gencodec:"required"`
+	TxRoot    common.Hash         `json:"txRoot" gencodec:"required"`
+	Proof     []merkle.MerkleNode `json:"proof" gencodec:"required"`
+}
+
+type txProofMarshaling struct {
+	Height hexutil.Uint32
+}
+
+//go:generate gencodec -type ChangeLogProof --field-override changeLogProofMarshaling -out gen_change_log_proof_json.go
+
+// ChangeLogProof proves the change log is in block by the LogRoot in block header
+type ChangeLogProof struct {
+	ChangeLog *types.ChangeLog    `json:"changeLog" gencodec:"required"`
+	BlockHash common.Hash         `json:"blockHash" gencodec:"required"`
+	Height    uint32              `json:"height" gencodec:"required"`
+	LogRoot   common.Hash         `json:"changeLogRoot" gencodec:"required"`
+	Proof     []merkle.MerkleNode `json:"proof" gencodec:"required"`
+}
+
+type changeLogProofMarshaling struct {
+	Height hexutil.Uint32
+}
+
+// PublicProofAPI API for the merkle proofs of block content
+type PublicProofAPI struct {
+	node *Node
+}
+
+// NewPublicProofAPI
+func NewPublicProofAPI(node *Node) *PublicProofAPI {
+	return &PublicProofAPI{node}
+}
+
+// GetTxProof returns the merkle proof of a stable transaction. It can be verified with the TxRoot in header by merkle.Verify
+func (p *PublicProofAPI) GetTxProof(txHash common.Hash) (*TxProof, error) {
+	detail, err := p.node.db.GetBizDatabase().GetTxByHash(txHash)
+	if err == store.ErrNotExist {
+		return nil, ErrTxNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	block := p.node.chain.GetBlockByHash(detail.BlockHash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+
+	leafHash := txHash
+	if (detail.PHash != common.Hash{}) {
+		leafHash = detail.PHash
+	}
+	for i, tx := range block.Txs {
+		if tx.Hash() != leafHash {
+			continue
+		}
+		proof, err := block.Txs.MerkleProof(i)
+		if err != nil {
+			return nil, err
+		}
+		result := &TxProof{
+			Tx:        detail.Tx,
+			BlockHash: block.Hash(),
+			Height:    block.Height(),
+			TxRoot:    block.TxRoot(),
+			Proof:     proof,
+		}
+		if leafHash != txHash {
+			result.BoxTx = tx
+		}
+		return result, nil
+	}
+	return nil, ErrTxNotFound
+}
+
+// GetChangeLogProof returns the merkle proof of the change log in block. The change log is specified by account address, log type and version.
+// It can be verified with the LogRoot in header by merkle.Verify
+func (p *PublicProofAPI) GetChangeLogProof(blockHash common.Hash, lemoAddress string, logType uint32, version uint32) (*ChangeLogProof, error) {
+	address, err := common.StringToAddress(lemoAddress)
+	if err != nil {
+		return nil, err
+	}
+	block := p.node.chain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+
+	for i, cl := range block.ChangeLogs {
+		if cl.Address != address || cl.LogType != types.ChangeLogType(logType) || cl.Version != version {
+			continue
+		}
+		proof, err := block.ChangeLogs.MerkleProof(i)
+		if err != nil {
+			return nil, err
+		}
+		return &ChangeLogProof{
+			ChangeLog: cl,
+			BlockHash: block.Hash(),
+			Height:    block.Height(),
+			LogRoot:   block.LogRoot(),
+			Proof:     proof,
+		}, nil
+	}
+	return nil, ErrChangeLogNotFound
+}
@@ -0,0 +1,203 @@
+package node
+
+import (
+	"context"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/consensus"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common/log"
+	"github.com/LemoFoundationLtd/lemochain-core/common/subscribe"
+	"github.com/LemoFoundationLtd/lemochain-core/network/rpc"
+	"github.com/LemoFoundationLtd/lemochain-core/store"
+)
+
+const (
+	// blockChanSize is the size of channel listening to block events
+	blockChanSize = 10
+	// txChanSize is the size of channel listening to new transaction events
+	txChanSize = 4096
+)
+
+// PublicSubscribeAPI API for subscribing the chain events. It only works on the connections which support notifications, such as websocket and IPC.
+// The subscription is created by chain_subscribe with the event name as the first param, e.g. chain_subscribe("newStableBlocks")
+type PublicSubscribeAPI struct {
+	node *Node
+}
+
+// NewPublicSubscribeAPI
+func NewPublicSubscribeAPI(node *Node) *PublicSubscribeAPI {
+	return &PublicSubscribeAPI{node}
+}
+
+// NewBlocks pushes the block every time the current block changes. The blocks may be not continuous, and may be switched to another fork
+func (s *PublicSubscribeAPI) NewBlocks(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	blockCh := make(chan *types.Block, blockChanSize)
+	blockSub := s.node.chain.SubscribeCurrent(blockCh)
+	go func() {
+		defer blockSub.Unsubscribe()
+		for {
+			select {
+			case block := <-blockCh:
+				if err := notifier.Notify(rpcSub.ID, block); err != nil {
+					log.Debugf("notify new block fail: %v", err)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// SwitchForks pushes the old and new head block headers every time the current block switches to another fork
+func (s *PublicSubscribeAPI) SwitchForks(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	eventCh := make(chan *consensus.SwitchForkEvent, blockChanSize)
+	eventSub := s.node.chain.SubscribeSwitchFork(eventCh)
+	go func() {
+		defer eventSub.Unsubscribe()
+		for {
+			select {
+			case event := <-eventCh:
+				if err := notifier.Notify(rpcSub.ID, event); err != nil {
+					log.Debugf("notify switch fork fail: %v", err)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// NewStableBlocks pushes every new stable block in height order. No height will be skipped
+func (s *PublicSubscribeAPI) NewStableBlocks(ctx context.Context) (*rpc.Subscription, error) {
+	return s.subscribeStable(ctx, func(notifier *rpc.Notifier, id rpc.ID, from, to uint32) {
+		for height := from; height <= to; height++ {
+			block := s.node.chain.GetBlockByHeight(height)
+			if block == nil {
+				continue
+			}
+			if err := notifier.Notify(id, block); err != nil {
+				log.Debugf("notify new stable block fail: %v", err)
+				return
+			}
+		}
+	})
+}
+
+// Events pushes the events in new stable blocks which match the query. The FromHeight and ToHeight in query are ignored
+func (s *PublicSubscribeAPI) Events(ctx context.Context, query EventQuery) (*rpc.Subscription, error) {
+	return s.subscribeStable(ctx, func(notifier *rpc.Notifier, id rpc.ID, from, to uint32) {
+		for start := from; start <= to; start += store.MaxEventsQueryRange {
+			end := to
+			if end-start >= store.MaxEventsQueryRange {
+				end = start + store.MaxEventsQueryRange - 1
+			}
+			events, err := s.node.db.GetBizDatabase().GetEvents(start, end, query.Addresses, query.Topics)
+			if err != nil {
+				log.Errorf("load events from %d to %d fail: %v", start, end, err)
+				return
+			}
+			for _, event := range events {
+				if err := notifier.Notify(id, event); err != nil {
+					log.Debugf("notify event fail: %v", err)
+					return
+				}
+			}
+			if end == to {
+				return
+			}
+		}
+	})
+}
+
+// PendingTxs pushes the transactions which are received by tx pool
+func (s *PublicSubscribeAPI) PendingTxs(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	txCh := make(chan *types.Transaction, txChanSize)
+	subscribe.Sub(subscribe.NewTx, txCh)
+	go func() {
+		defer unSubTx(txCh)
+		for {
+			select {
+			case tx := <-txCh:
+				if err := notifier.Notify(rpcSub.ID, tx); err != nil {
+					log.Debugf("notify pending tx fail: %v", err)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// subscribeStable calls handle with the height range of new stable blocks every time the stable block changes
+func (s *PublicSubscribeAPI) subscribeStable(ctx context.Context, handle func(notifier *rpc.Notifier, id rpc.ID, from, to uint32)) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	blockCh := make(chan *types.Block, blockChanSize)
+	blockSub := s.node.chain.SubscribeStable(blockCh)
+	lastHeight := s.node.chain.StableBlock().Height()
+	go func() {
+		defer blockSub.Unsubscribe()
+		for {
+			select {
+			case block := <-blockCh:
+				// the stable blocks are sent in different goroutines, so an older block may come later
+				if block.Height() <= lastHeight {
+					continue
+				}
+				handle(notifier, rpcSub.ID, lastHeight+1, block.Height())
+				lastHeight = block.Height()
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// unSubTx removes the channel from subscribe.NewTx. The channel must be drained until it is removed, or the sender may be blocked
+func unSubTx(txCh chan *types.Transaction) {
+	done := make(chan struct{})
+	go func() {
+		subscribe.UnSub(subscribe.NewTx, txCh)
+		close(done)
+	}()
+	for {
+		select {
+		case <-txCh:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
+	"github.com/LemoFoundationLtd/lemochain-core/network/rpc"
+	"gopkg.in/urfave/cli.v1"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSignerName       = "default"
+	reimbursementSignerName = "reimbursement"
+	gasPayerSignerName      = "gasPayer"
+)
+
+var (
+	ErrMissingFrom     = errors.New("missing --from")
+	ErrMissingChainID  = errors.New("missing --chainID")
+	ErrMissingKey      = errors.New("missing --key")
+	ErrMissingRPC      = errors.New("missing --rpc")
+	ErrUnknownTxType   = errors.New("unknown transaction type")
+	ErrUnknownSigner   = errors.New("unknown signer. it should be default, reimbursement or gasPayer")
+	ErrInvalidAmount   = errors.New("invalid number")
+	ErrNeedTxRecipient = errors.New("the transaction type needs --to")
+)
+
+var (
+	txTypeFlag = cli.UintFlag{
+		Name:  "type",
+		Usage: "Transaction type, such as 0(ordinary), 1(create contract), 2(vote), 8(transfer asset), 10(box)",
+	}
+	txChainIDFlag = cli.UintFlag{
+		Name:  "chainID",
+		Usage: "Chain ID, 1 for main net and 100 for develop net",
+	}
+	txFromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Sender address",
+	}
+	txToFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Recipient address",
+	}
+	txToNameFlag = cli.StringFlag{
+		Name:  "toName",
+		Usage: "Recipient name",
+	}
+	txGasPayerFlag = cli.StringFlag{
+		Name:  "gasPayer",
+		Usage: "The address which pays gas instead of sender",
+	}
+	txAmountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "Amount in mo",
+		Value: "0",
+	}
+	txGasPriceFlag = cli.StringFlag{
+		Name:  "gasPrice",
+		Usage: "Gas price in mo",
+	}
+	txGasLimitFlag = cli.Uint64Flag{
+		Name:  "gasLimit",
+		Usage: "Gas limit",
+		Value: 2000000,
+	}
+	txDataFlag = cli.StringFlag{
+		Name:  "data",
+		Usage: "Transaction data in hex, or json for special transactions",
+	}
+	txExpirationFlag = cli.Uint64Flag{
+		Name:  "expiration",
+		Usage: "Expiration time in UTC seconds. It is 30 minutes later by default",
+	}
+	txMessageFlag = cli.StringFlag{
+		Name:  "message",
+		Usage: "Transaction message",
+	}
+	txKeyFlag = cli.StringFlag{
+		Name:  "key",
+		Usage: "Private key in hex",
+	}
+	txSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "Signer type: default, reimbursement or gasPayer",
+		Value: defaultSignerName,
+	}
+	txRPCFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "The endpoint of glemo node, such as http://127.0.0.1:8001 or ipc path",
+	}
+
+	txCommand = cli.Command{
+		Name:     "tx",
+		Usage:    "Build, sign, inspect and send transactions offline",
+		Category: "TRANSACTION COMMANDS",
+		Description: `
+Manage transactions for cold-wallet workflows. The transaction is read from
+the file in argument, or from standard input if the argument is missing.`,
+		Subcommands: []cli.Command{
+			{
+				Action: buildTxCmd,
+				Name:   "build",
+				Usage:  "Build an unsigned transaction in json",
+				Flags: []cli.Flag{
+					txTypeFlag, txChainIDFlag, txFromFlag, txToFlag, txToNameFlag, txGasPayerFlag, txAmountFlag,
+					txGasPriceFlag, txGasLimitFlag, txDataFlag, txExpirationFlag, txMessageFlag,
+				},
+			},
+			{
+				Action:    signTxCmd,
+				Name:      "sign",
+				Usage:     "Sign a transaction with private key",
+				ArgsUsage: "[txFile]",
+				Flags:     []cli.Flag{txKeyFlag, txSignerFlag, txGasPriceFlag, txGasLimitFlag},
+			},
+			{
+				Action:    inspectTxCmd,
+				Name:      "inspect",
+				Usage:     "Decode a transaction, show its hash and signers",
+				ArgsUsage: "[txFile]",
+			},
+			{
+				Action:    sendTxCmd,
+				Name:      "send",
+				Usage:     "Send a signed transaction to glemo node",
+				ArgsUsage: "[txFile]",
+				Flags:     []cli.Flag{txRPCFlag},
+			},
+		},
+	}
+)
+
+// txBuildArgs is the fields of transaction to build
+type txBuildArgs struct {
+	Type       uint16
+	ChainID    uint16
+	From       string
+	To         string
+	ToName     string
+	GasPayer   string
+	Amount     string
+	GasPrice   string
+	GasLimit   uint64
+	Data       string
+	Expiration uint64
+	Message    string
+}
+
+// txInspection is the decoded information of transaction
+type txInspection struct {
+	Hash            common.Hash        `json:"hash"`
+	Type            uint16             `json:"type"`
+	ChainID         uint16             `json:"chainID"`
+	From            common.Address     `json:"from"`
+	GasPayer        common.Address     `json:"gasPayer"`
+	Expiration      uint64             `json:"expirationTime"`
+	Signers         []common.Address   `json:"signers"`
+	GasPayerSigners []common.Address   `json:"gasPayerSigners,omitempty"`
+	Tx              *types.Transaction `json:"tx"`
+}
+
+func parseBig(value string) (*big.Int, error) {
+	result, ok := new(big.Int).SetString(value, 10)
+	if !ok || result.Sign() < 0 {
+		return nil, ErrInvalidAmount
+	}
+	return result, nil
+}
+
+// parseTxData decodes the hex data. The special transactions take json as data, so the data is kept as it is if it is not hex
+func parseTxData(data string) []byte {
+	if data == "" {
+		return nil
+	}
+	if strings.HasPrefix(data, "0x") {
+		if decoded, err := hexutil.Decode(data); err == nil {
+			return decoded
+		}
+	}
+	return []byte(data)
+}
+
+// buildTx creates an unsigned transaction
+func buildTx(args *txBuildArgs) (*types.Transaction, error) {
+	if args.ChainID == 0 {
+		return nil, ErrMissingChainID
+	}
+	if args.Type > params.BoxTx {
+		return nil, ErrUnknownTxType
+	}
+	if args.From == "" {
+		return nil, ErrMissingFrom
+	}
+	from, err := common.StringToAddress(args.From)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseBig(args.Amount)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := new(big.Int).Set(params.MinGasPrice)
+	if args.GasPrice != "" {
+		if gasPrice, err = parseBig(args.GasPrice); err != nil {
+			return nil, err
+		}
+	}
+	expiration := args.Expiration
+	if expiration == 0 {
+		expiration = uint64(time.Now().Unix()) + uint64(params.TransactionExpiration)
+	}
+	data := parseTxData(args.Data)
+
+	var to *common.Address
+	if args.To != "" {
+		address, err := common.StringToAddress(args.To)
+		if err != nil {
+			return nil, err
+		}
+		to = &address
+	} else if args.Type == params.OrdinaryTx || args.Type == params.TransferAssetTx {
+		return nil, ErrNeedTxRecipient
+	}
+
+	var tx *types.Transaction
+	if args.GasPayer != "" {
+		gasPayer, err := common.StringToAddress(args.GasPayer)
+		if err != nil {
+			return nil, err
+		}
+		if to == nil {
+			tx = types.NewReimbursementContractCreation(from, gasPayer, amount, data, args.Type, args.ChainID, expiration, args.ToName, args.Message)
+		} else {
+			tx = types.NewReimbursementTransaction(from, *to, gasPayer, amount, data, args.Type, args.ChainID, expiration, args.ToName, args.Message)
+		}
+	} else if to == nil {
+		tx = types.NoReceiverTransaction(from, amount, args.GasLimit, gasPrice, data, args.Type, args.ChainID, expiration, args.ToName, args.Message)
+	} else {
+		tx = types.NewTransaction(from, *to, amount, args.GasLimit, gasPrice, data, args.Type, args.ChainID, expiration, args.ToName, args.Message)
+	}
+	// the sigs field is required in json, so an empty list is output for unsigned transaction
+	return tx.WithSigs([][]byte{}), nil
+}
+
+// signTx signs the transaction with private key. The gas price and gas limit are set by gas payer if they are not nil
+func signTx(tx *types.Transaction, hexKey string, signerName string, gasPrice *big.Int, gasLimit uint64) (*types.Transaction, error) {
+	if hexKey == "" {
+		return nil, ErrMissingKey
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	switch signerName {
+	case defaultSignerName:
+		return types.MakeSigner().SignTx(tx, privateKey)
+	case reimbursementSignerName:
+		return types.MakeReimbursementTxSigner().SignTx(tx, privateKey)
+	case gasPayerSignerName:
+		if gasPrice != nil {
+			tx = types.GasPayerSignatureTx(tx, gasPrice, gasLimit)
+		}
+		return types.MakeGasPayerSigner().SignTx(tx, privateKey)
+	default:
+		return nil, ErrUnknownSigner
+	}
+}
+
+// inspectTx recovers the signers of transaction
+func inspectTx(tx *types.Transaction) (*txInspection, error) {
+	result := &txInspection{
+		Hash:       tx.Hash(),
+		Type:       tx.Type(),
+		ChainID:    tx.ChainID(),
+		From:       tx.From(),
+		GasPayer:   tx.GasPayer(),
+		Expiration: tx.Expiration(),
+		Signers:    []common.Address{},
+		Tx:         tx,
+	}
+	fromSigner := types.MakeSigner()
+	if tx.GasPayer() != tx.From() {
+		fromSigner = types.MakeReimbursementTxSigner()
+		if len(tx.GasPayerSigs()) > 0 {
+			signers, err := types.MakeGasPayerSigner().GetSigners(tx)
+			if err != nil {
+				return nil, err
+			}
+			result.GasPayerSigners = signers
+		}
+	}
+	if len(tx.Sigs()) > 0 {
+		signers, err := fromSigner.GetSigners(tx)
+		if err != nil {
+			return nil, err
+		}
+		result.Signers = signers
+	}
+	return result, nil
+}
+
+// readTx reads the transaction json from file, or from stdin if the file is empty
+func readTx(file string) (*types.Transaction, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if file == "" || file == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := json.Unmarshal(content, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func printJSON(value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(content))
+	return nil
+}
+
+func buildTxCmd(ctx *cli.Context) error {
+	tx, err := buildTx(&txBuildArgs{
+		Type:       uint16(ctx.Uint(txTypeFlag.Name)),
+		ChainID:    uint16(ctx.Uint(txChainIDFlag.Name)),
+		From:       ctx.String(txFromFlag.Name),
+		To:         ctx.String(txToFlag.Name),
+		ToName:     ctx.String(txToNameFlag.Name),
+		GasPayer:   ctx.String(txGasPayerFlag.Name),
+		Amount:     ctx.String(txAmountFlag.Name),
+		GasPrice:   ctx.String(txGasPriceFlag.Name),
+		GasLimit:   ctx.Uint64(txGasLimitFlag.Name),
+		Data:       ctx.String(txDataFlag.Name),
+		Expiration: ctx.Uint64(txExpirationFlag.Name),
+		Message:    ctx.String(txMessageFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(tx)
+}
+
+func signTxCmd(ctx *cli.Context) error {
+	tx, err := readTx(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	var gasPrice *big.Int
+	if ctx.IsSet(txGasPriceFlag.Name) {
+		if gasPrice, err = parseBig(ctx.String(txGasPriceFlag.Name)); err != nil {
+			return err
+		}
+	}
+	signedTx, err := signTx(tx, ctx.String(txKeyFlag.Name), ctx.String(txSignerFlag.Name), gasPrice, ctx.Uint64(txGasLimitFlag.Name))
+	if err != nil {
+		return err
+	}
+	return printJSON(signedTx)
+}
+
+func inspectTxCmd(ctx *cli.Context) error {
+	tx, err := readTx(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	inspection, err := inspectTx(tx)
+	if err != nil {
+		return err
+	}
+	return printJSON(inspection)
+}
+
+func sendTxCmd(ctx *cli.Context) error {
+	endpoint := ctx.String(txRPCFlag.Name)
+	if endpoint == "" {
+		return ErrMissingRPC
+	}
+	tx, err := readTx(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var hash common.Hash
+	if err := client.Call(&hash, "tx_sendTx", tx); err != nil {
+		return err
+	}
+	fmt.Println(hash.Hex())
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func Test_buildTx(t *testing.T) {
+	fromKey, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(fromKey.PublicKey)
+	to := common.HexToAddress("0x1")
+
+	// missing fields
+	_, err := buildTx(&txBuildArgs{From: from.String(), Amount: "1"})
+	assert.Equal(t, ErrMissingChainID, err)
+	_, err = buildTx(&txBuildArgs{ChainID: 100, Amount: "1"})
+	assert.Equal(t, ErrMissingFrom, err)
+	_, err = buildTx(&txBuildArgs{ChainID: 100, From: from.String(), Amount: "1"})
+	assert.Equal(t, ErrNeedTxRecipient, err)
+	_, err = buildTx(&txBuildArgs{ChainID: 100, From: from.String(), To: to.String(), Amount: "-1"})
+	assert.Equal(t, ErrInvalidAmount, err)
+	_, err = buildTx(&txBuildArgs{Type: 100, ChainID: 100, From: from.String(), Amount: "1"})
+	assert.Equal(t, ErrUnknownTxType, err)
+
+	// ordinary transaction
+	tx, err := buildTx(&txBuildArgs{ChainID: 100, From: from.String(), To: to.String(), Amount: "100", GasLimit: 21000, Data: "0x1234", Expiration: 1000, Message: "hi"})
+	assert.NoError(t, err)
+	assert.Equal(t, params.OrdinaryTx, tx.Type())
+	assert.Equal(t, from, tx.From())
+	assert.Equal(t, to, *tx.To())
+	assert.Equal(t, big.NewInt(100), tx.Amount())
+	assert.Equal(t, params.MinGasPrice, tx.GasPrice())
+	assert.Equal(t, []byte{0x12, 0x34}, tx.Data())
+	assert.Equal(t, uint64(1000), tx.Expiration())
+
+	// unsigned transaction can be decoded from json
+	content, err := json.Marshal(tx)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"sigs":[]`)
+	decoded := new(types.Transaction)
+	assert.NoError(t, json.Unmarshal(content, decoded))
+	assert.Equal(t, tx.Hash(), decoded.Hash())
+
+	// special transaction takes json data without recipient
+	tx, err = buildTx(&txBuildArgs{Type: params.CreateAssetTx, ChainID: 100, From: from.String(), Amount: "0", Data: `{"category":1}`})
+	assert.NoError(t, err)
+	assert.Nil(t, tx.To())
+	assert.Equal(t, []byte(`{"category":1}`), tx.Data())
+}
+
+func Test_signTx(t *testing.T) {
+	fromKey, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(fromKey.PublicKey)
+	gasPayerKey, _ := crypto.GenerateKey()
+	gasPayer := crypto.PubkeyToAddress(gasPayerKey.PublicKey)
+	fromHexKey := common.ToHex(crypto.FromECDSA(fromKey))
+
+	tx, err := buildTx(&txBuildArgs{ChainID: 100, From: from.String(), To: common.HexToAddress("0x1").String(), Amount: "1"})
+	assert.NoError(t, err)
+	_, err = signTx(tx, "", defaultSignerName, nil, 0)
+	assert.Equal(t, ErrMissingKey, err)
+	_, err = signTx(tx, fromHexKey, "unknown", nil, 0)
+	assert.Equal(t, ErrUnknownSigner, err)
+
+	// unsigned
+	inspection, err := inspectTx(tx)
+	assert.NoError(t, err)
+	assert.Empty(t, inspection.Signers)
+
+	// default signer
+	signedTx, err := signTx(tx, fromHexKey, defaultSignerName, nil, 0)
+	assert.NoError(t, err)
+	inspection, err = inspectTx(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, signedTx.Hash(), inspection.Hash)
+	assert.Equal(t, []common.Address{from}, inspection.Signers)
+	assert.Empty(t, inspection.GasPayerSigners)
+
+	// reimbursement transaction
+	tx, err = buildTx(&txBuildArgs{ChainID: 100, From: from.String(), To: common.HexToAddress("0x1").String(), GasPayer: gasPayer.String(), Amount: "1"})
+	assert.NoError(t, err)
+	signedTx, err = signTx(tx, fromHexKey, reimbursementSignerName, nil, 0)
+	assert.NoError(t, err)
+	signedTx, err = signTx(signedTx, common.ToHex(crypto.FromECDSA(gasPayerKey)), gasPayerSignerName, big.NewInt(3000000000), 50000)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3000000000), signedTx.GasPrice())
+	assert.Equal(t, uint64(50000), signedTx.GasLimit())
+	inspection, err = inspectTx(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, gasPayer, inspection.GasPayer)
+	assert.Equal(t, []common.Address{from}, inspection.Signers)
+	assert.Equal(t, []common.Address{gasPayer}, inspection.GasPayerSigners)
+}
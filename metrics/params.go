@@ -7,6 +7,8 @@ var (
 	txpoolModule             = "txpool"
 	InvalidTx_meterName      = "txpool/DelInvalidTxs/invalid"
 	TxpoolNumber_counterName = "txpool/totalTxNumber"
+	RejectedTx_meterName     = "txpool/RecvTx/rejected" // 统计交易池满或gas price过低而拒收交易的频率
+	EvictedTx_meterName      = "txpool/RecvTx/evicted"  // 统计交易池满时淘汰低价交易的频率
 
 	// tx
 	txModule                 = "tx"
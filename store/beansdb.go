@@ -62,6 +62,9 @@ func (beansdb *BeansDB) After(flg uint32, key []byte, val []byte) error {
 	} else if flg == leveldb.ItemFlagAssetId {
 		log.Infof("after flag: ItemFlagAssetId")
 		return nil
+	} else if flg == leveldb.ItemFlagReceipt {
+		log.Infof("after flag: ItemFlagReceipt")
+		return nil
 	} else {
 		panic("after! unknown flag.flag = " + strconv.Itoa(int(flg)))
 	}
@@ -100,3 +100,22 @@ func UtilsSetAssetCode(db *BeansDB, code common.Hash, address common.Address) er
 func UtilsSetAssetId(db *BeansDB, id common.Hash, code common.Hash) error {
 	return db.Put(leveldb.ItemFlagAssetId, id.Bytes(), code.Bytes())
 }
+
+func UtilsGetReceipt(db *BeansDB, txHash common.Hash) (*types.Receipt, error) {
+	val, err := db.Get(leveldb.ItemFlagReceipt, txHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if val == nil {
+		return nil, nil
+	}
+
+	var receipt types.Receipt
+	err = rlp.DecodeBytes(val, &receipt)
+	if err != nil {
+		return nil, err
+	} else {
+		return &receipt, nil
+	}
+}
@@ -1,7 +1,6 @@
 package store
 
 import (
-	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
@@ -9,6 +8,7 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
 	"github.com/LemoFoundationLtd/lemochain-core/store/leveldb"
+	"math/big"
 	"strconv"
 )
 
@@ -40,16 +40,103 @@ type vTransactionDetailMarshaling struct {
 	PackageTime hexutil.Uint32
 }
 
+//go:generate gencodec -type VEvent --field-override vEventMarshaling -out gen_vEvent_info_json.go
+type VEvent struct {
+	Event     *types.Event `json:"event" gencodec:"required"`
+	BlockHash common.Hash  `json:"blockHash" gencodec:"required"`
+	Height    uint32       `json:"height" gencodec:"required"`
+}
+
+type vEventMarshaling struct {
+	Height hexutil.Uint32
+}
+
+//go:generate gencodec -type VInternalTx --field-override vInternalTxMarshaling -out gen_vInternalTx_info_json.go
+type VInternalTx struct {
+	InternalTx *types.InternalTx `json:"internalTx" gencodec:"required"`
+	TxHash     common.Hash       `json:"txHash" gencodec:"required"`
+	BlockHash  common.Hash       `json:"blockHash" gencodec:"required"`
+	Height     uint32            `json:"height" gencodec:"required"`
+}
+
+type vInternalTxMarshaling struct {
+	Height hexutil.Uint32
+}
+
+//go:generate gencodec -type VAssetHolder --field-override vAssetHolderMarshaling -out gen_vAssetHolder_info_json.go
+type VAssetHolder struct {
+	Address   common.Address `json:"address" gencodec:"required"`
+	AssetCode common.Hash    `json:"assetCode" gencodec:"required"`
+	AssetId   common.Hash    `json:"assetId" gencodec:"required"`
+	Equity    *big.Int       `json:"equity" gencodec:"required"`
+}
+
+type vAssetHolderMarshaling struct {
+	Equity *hexutil.Big10
+}
+
 type BizDb interface {
 	GetTxByHash(hash common.Hash) (*VTransactionDetail, error)
 
 	GetTxByAddr(src common.Address, index int, size int) ([]*VTransaction, uint32, error)
+
+	GetTxByAssetCode(src common.Address, code common.Hash, index int, size int) ([]*VTransaction, uint32, error)
+
+	GetTxByAssetId(src common.Address, id common.Hash, index int, size int) ([]*VTransaction, uint32, error)
+
+	GetBlockBloom(height uint32) (types.Bloom, error)
+
+	GetEvents(fromHeight, toHeight uint32, addresses []common.Address, topics [][]common.Hash) ([]*VEvent, error)
+
+	GetInternalTxByAddr(src common.Address, index int, size int) ([]*VInternalTx, uint32, error)
+
+	GetAssetHolders(code common.Hash, index int, size int) ([]*VAssetHolder, uint32, error)
+
+	GetAssetTransfers(id common.Hash, index int, size int) ([]*VTransaction, uint32, error)
 }
 
 type Reader interface {
 	GetLastConfirm() *CBlock
 
 	GetBlockByHash(hash common.Hash) (*types.Block, error)
+
+	GetBlockByHeight(height uint32) (*types.Block, error)
+
+	GetReceipt(txHash common.Hash) (*types.Receipt, error)
+}
+
+// MaxEventsQueryRange is the max count of blocks which can be searched in one events query
+const MaxEventsQueryRange = 10000
+
+// txPosition records where the transaction is in stable chain
+type txPosition struct {
+	BlockHash common.Hash
+	Height    uint32
+	Index     uint32      // the index of tx in block. It is the index of box if the tx is a sub transaction
+	SubIndex  uint32      // the index of tx in box
+	PHash     common.Hash // the hash of box transaction which contains the tx
+	AssetCode common.Hash
+	AssetId   common.Hash
+}
+
+// pickTx find the transaction from block by position
+func (pos *txPosition) pickTx(block *types.Block) (*types.Transaction, error) {
+	if int(pos.Index) >= len(block.Txs) {
+		return nil, ErrNotExist
+	}
+	tx := block.Txs[pos.Index]
+	if (pos.PHash == common.Hash{}) {
+		return tx, nil
+	}
+
+	box, err := types.GetBox(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	if int(pos.SubIndex) >= len(box.SubTxList) {
+		return nil, ErrNotExist
+	}
+	return box.SubTxList[pos.SubIndex], nil
 }
 
 type BizDatabase struct {
@@ -64,82 +151,146 @@ func NewBizDatabase(reader Reader, levelDB *leveldb.LevelDBDatabase) *BizDatabas
 	}
 }
 
+func (db *BizDatabase) getTxPosition(hash common.Hash) (*txPosition, error) {
+	val, err := leveldb.Get(db.LevelDB, leveldb.GetTxIndexKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(val) <= 0 {
+		return nil, ErrNotExist
+	}
+
+	var pos txPosition
+	err = rlp.DecodeBytes(val, &pos)
+	if err != nil {
+		return nil, err
+	} else {
+		return &pos, nil
+	}
+}
+
 func (db *BizDatabase) GetTxByHash(hash common.Hash) (*VTransactionDetail, error) {
-	// blockHash, val, st, err := db.Database.TxGetByHash(hash.Hex())
-	// if err != nil {
-	// 	return nil, err
-	// }
-	//
-	// block, err := db.Reader.GetBlockByHash(common.HexToHash(blockHash))
-	// if err == ErrNotExist {
-	// 	return nil, ErrNotExist
-	// }
-	//
-	// if err != nil {
-	// 	return nil, err
-	// }
-	//
-	// var tx types.Transaction
-	// err = rlp.DecodeBytes(val, &tx)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	//
-	// return &VTransactionDetail{
-	// 	BlockHash: block.Hash(),
-	// 	Height:    block.Height(),
-	// 	Tx:        &tx,
-	// 	St:        st,
-	// }, nil
-	return nil, nil
+	pos, err := db.getTxPosition(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := db.Reader.GetBlockByHash(pos.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pos.pickTx(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VTransactionDetail{
+		BlockHash:   pos.BlockHash,
+		PHash:       pos.PHash,
+		Height:      pos.Height,
+		Tx:          tx,
+		PackageTime: block.Time(),
+		AssetCode:   pos.AssetCode,
+		AssetId:     pos.AssetId,
+	}, nil
 }
 
 func (db *BizDatabase) GetTxByAddr(src common.Address, index int, size int) ([]*VTransaction, uint32, error) {
-	// if (index < 0) || (size > 200) || (size <= 0) {
-	// 	return nil, 0, errors.New("argment error.")
-	// }
-	//
-	// confirm := db.Reader.GetLastConfirm()
-	// trieDB := confirm.AccountTrieDB
-	// if trieDB == nil {
-	// 	return make([]*VTransaction, 0), 0, nil
-	// }
-	//
-	// account, err := trieDB.Get(src)
-	// if err != nil {
-	// 	return nil, 0, err
-	// }
-	//
-	// if account == nil {
-	// 	return make([]*VTransaction, 0), 0, nil
-	// }
-	//
-	// txCount := uint32(0)
-	// // txCount := account.TxCount
-	// // if uint32(index) > txCount {
-	// // 	return make([]*VTransaction, 0), txCount, nil
-	// // }
-	//
-	// _, vals, sts, err := db.Database.TxGetByAddr(src.Hex(), index, size)
-	// if err != nil {
-	// 	return nil, 0, err
-	// }
-	//
-	// txs := make([]*VTransaction, len(vals))
-	// for index := 0; index < len(vals); index++ {
-	// 	var tx types.Transaction
-	// 	err = rlp.DecodeBytes(vals[index], &tx)
-	// 	if err != nil {
-	// 		return nil, 0, err
-	// 	}
-	//
-	// 	txs[index] = &VTransaction{
-	// 		Tx: &tx,
-	// 		St: sts[index],
-	// 	}
-	// }
-	// return txs, txCount, nil
-	return nil, 0, nil
+	return db.getTxList(leveldb.GetAddrTxListKey(src), index, size)
+}
+
+func (db *BizDatabase) GetTxByAssetCode(src common.Address, code common.Hash, index int, size int) ([]*VTransaction, uint32, error) {
+	return db.getTxList(leveldb.GetAddrAssetCodeTxListKey(src, code), index, size)
+}
+
+func (db *BizDatabase) GetTxByAssetId(src common.Address, id common.Hash, index int, size int) ([]*VTransaction, uint32, error) {
+	return db.getTxList(leveldb.GetAddrAssetIdTxListKey(src, id), index, size)
+}
+
+// GetAssetTransfers load the transactions which issue, replenish or transfer the asset id by page. The transactions are sorted from old to new
+func (db *BizDatabase) GetAssetTransfers(id common.Hash, index int, size int) ([]*VTransaction, uint32, error) {
+	return db.getTxList(leveldb.GetAssetIdTxListKey(id), index, size)
+}
+
+func (db *BizDatabase) getTxListSize(listKey []byte) (uint32, error) {
+	val, err := leveldb.Get(db.LevelDB, leveldb.GetTxListSizeKey(listKey))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(val) <= 0 {
+		return 0, nil
+	} else {
+		return leveldb.DecodeNumber(val), nil
+	}
+}
+
+// getTxList load transactions from tx list by page. The transactions are sorted from old to new
+func (db *BizDatabase) getTxList(listKey []byte, index int, size int) ([]*VTransaction, uint32, error) {
+	if (index < 0) || (size > 200) || (size <= 0) {
+		return nil, 0, ErrArgInvalid
+	}
+
+	total, err := db.getTxListSize(listKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if uint32(index) >= total {
+		return make([]*VTransaction, 0), total, nil
+	}
+
+	hashes := make([]common.Hash, 0, size)
+	iterator := db.LevelDB.NewIteratorWithPrefix(listKey)
+	for skip := 0; iterator.Next(); skip++ {
+		if skip < index {
+			continue
+		}
+
+		hashes = append(hashes, common.BytesToHash(iterator.Value()))
+		if len(hashes) >= size {
+			break
+		}
+	}
+	iterator.Release()
+	if err := iterator.Error(); err != nil {
+		return nil, 0, err
+	}
+
+	// the transactions in one page are usually in the same block
+	blocks := make(map[common.Hash]*types.Block)
+	result := make([]*VTransaction, 0, len(hashes))
+	for _, hash := range hashes {
+		pos, err := db.getTxPosition(hash)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		block, ok := blocks[pos.BlockHash]
+		if !ok {
+			block, err = db.Reader.GetBlockByHash(pos.BlockHash)
+			if err != nil {
+				return nil, 0, err
+			}
+			blocks[pos.BlockHash] = block
+		}
+
+		tx, err := pos.pickTx(block)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		result = append(result, &VTransaction{
+			Tx:          tx,
+			PHash:       pos.PHash,
+			PackageTime: block.Time(),
+			AssetCode:   pos.AssetCode,
+			AssetId:     pos.AssetId,
+		})
+	}
+	return result, total, nil
 }
 
 func (db *BizDatabase) AfterCommit(flag uint32, key []byte, val []byte) error {
@@ -157,6 +308,8 @@ func (db *BizDatabase) AfterCommit(flag uint32, key []byte, val []byte) error {
 		return nil
 	} else if flag == leveldb.ItemFlagKV {
 		return nil
+	} else if flag == leveldb.ItemFlagReceipt {
+		return db.afterReceipt(key, val)
 	} else {
 		panic("unknown flag.flag = " + strconv.Itoa(int(flag)))
 	}
@@ -165,34 +318,553 @@ func (db *BizDatabase) AfterCommit(flag uint32, key []byte, val []byte) error {
 func (db *BizDatabase) afterBlock(key []byte, val []byte) error {
 	var block types.Block
 	err := rlp.DecodeBytes(val, &block)
-	ret := hexutil.Encode(val)
-	fmt.Sprintf(ret)
 	if err != nil {
 		return err
 	}
 
+	if err = db.indexEquities(block.ChangeLogs); err != nil {
+		return err
+	}
+
 	txs := block.Txs
 	if len(txs) <= 0 {
 		return nil
 	}
 
-	// ver := time.Now().UnixNano()
+	hash := block.Hash()
+	seq := uint32(0)
 	for index := 0; index < len(txs); index++ {
 		tx := txs[index]
-		// hash := tx.Hash()
-		from := tx.From()
+		pos := &txPosition{
+			BlockHash: hash,
+			Height:    block.Height(),
+			Index:     uint32(index),
+		}
+		// a broken index should not hide the other txs in block
+		if err = db.indexTx(tx, pos, seq); err != nil {
+			log.Errorf("index tx fail. tx: %s, err: %v", tx.Hash().Hex(), err)
+		}
+		seq++
 
-		if tx.Type() == params.CreateAssetTx {
-			log.Info("insert account code: " + tx.Hash().Hex() + "|addr: " + from.Hex())
-			//return leveldb.Set(db.LevelDB, leveldb.GetAssetCodeKey(tx.Hash()), from.Bytes())
-		} else if tx.Type() == params.IssueAssetTx {
-			log.Info("insert account id: " + tx.Hash().Hex() + "|addr: " + from.Hex())
-			//return leveldb.Set(db.LevelDB, leveldb.GetAssetIdKey(tx.Hash()), from.Bytes())
+		if tx.Type() != params.BoxTx {
+			continue
 		}
 
+		box, err := types.GetBox(tx.Data())
+		if err != nil {
+			log.Errorf("index sub txs in box fail. tx: %s, err: %v", tx.Hash().Hex(), err)
+			continue
+		}
+		for subIndex, subTx := range box.SubTxList {
+			subPos := &txPosition{
+				BlockHash: hash,
+				Height:    block.Height(),
+				Index:     uint32(index),
+				SubIndex:  uint32(subIndex),
+				PHash:     tx.Hash(),
+			}
+			if err = db.indexTx(subTx, subPos, seq); err != nil {
+				log.Errorf("index tx fail. tx: %s, err: %v", subTx.Hash().Hex(), err)
+			}
+			seq++
+		}
+	}
+	return nil
+}
+
+// indexTx save the position of transaction, and append it to the tx lists of related accounts. The seq is the order of tx in block
+func (db *BizDatabase) indexTx(tx *types.Transaction, pos *txPosition, seq uint32) error {
+	hash := tx.Hash()
+	code, id, err := db.getTxAsset(tx)
+	if err != nil {
+		// the tx is still indexed without asset code
+		log.Warnf("get asset of tx fail. tx: %s, err: %v", hash.Hex(), err)
+		code = common.Hash{}
+	}
+	pos.AssetCode = code
+	pos.AssetId = id
+
+	buf, err := rlp.EncodeToBytes(pos)
+	if err != nil {
+		return err
+	}
+	err = leveldb.Set(db.LevelDB, leveldb.GetTxIndexKey(hash), buf)
+	if err != nil {
+		return err
+	}
+
+	if tx.Type() == params.IssueAssetTx {
+		log.Debug("insert asset id: " + id.Hex() + "|code: " + code.Hex())
+		err = leveldb.Set(db.LevelDB, leveldb.GetAssetIdCodeKey(id), code.Bytes())
 		if err != nil {
 			return err
 		}
 	}
+
+	for _, addr := range relatedAddresses(tx) {
+		err = db.appendTxList(leveldb.GetAddrTxListKey(addr), pos.Height, seq, hash)
+		if err != nil {
+			return err
+		}
+
+		if (code != common.Hash{}) {
+			err = db.appendTxList(leveldb.GetAddrAssetCodeTxListKey(addr, code), pos.Height, seq, hash)
+			if err != nil {
+				return err
+			}
+		}
+
+		if (id != common.Hash{}) {
+			err = db.appendTxList(leveldb.GetAddrAssetIdTxListKey(addr, id), pos.Height, seq, hash)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if (id != common.Hash{}) {
+		return db.appendTxList(leveldb.GetAssetIdTxListKey(id), pos.Height, seq, hash)
+	}
 	return nil
 }
+
+// EquityLogType is the type of equity change log. It is set by account package which defines the log types, because account depends on store
+var EquityLogType types.ChangeLogType
+
+// indexEquities update the asset holder lists by the equity change logs in stable block.
+// The log whose NewVal is nil means the equity is deleted
+func (db *BizDatabase) indexEquities(logs types.ChangeLogSlice) error {
+	for _, changeLog := range logs {
+		if changeLog.LogType != EquityLogType {
+			continue
+		}
+		if changeLog.NewVal == nil {
+			if err := db.removeAssetHolder(changeLog); err != nil {
+				return err
+			}
+			continue
+		}
+		equity, ok := changeLog.NewVal.(*types.AssetEquity)
+		if !ok || equity == nil {
+			continue
+		}
+		holder := &VAssetHolder{
+			Address:   changeLog.Address,
+			AssetCode: equity.AssetCode,
+			AssetId:   equity.AssetId,
+			Equity:    equity.Equity,
+		}
+		if err := db.setAssetHolder(holder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeAssetHolder removes the holder from the holder list of asset by the equity log whose NewVal is nil
+func (db *BizDatabase) removeAssetHolder(changeLog *types.ChangeLog) error {
+	id, ok := changeLog.Extra.(common.Hash)
+	if !ok {
+		return nil
+	}
+	code, err := db.getAssetCodeById(id)
+	if err != nil {
+		return err
+	}
+	return db.setAssetHolder(&VAssetHolder{Address: changeLog.Address, AssetCode: code, AssetId: id})
+}
+
+// getAssetCodeById returns the asset code of the asset id
+func (db *BizDatabase) getAssetCodeById(id common.Hash) (common.Hash, error) {
+	code, err := leveldb.Get(db.LevelDB, leveldb.GetAssetIdCodeKey(id))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(code) <= 0 {
+		// the asset id of token asset is equal to its asset code
+		return id, nil
+	}
+	return common.BytesToHash(code), nil
+}
+
+// setAssetHolder put the holder into the holder list of asset, or remove it if the equity is zero. It is safe to set a same holder for several times
+func (db *BizDatabase) setAssetHolder(holder *VAssetHolder) error {
+	listKey := leveldb.GetAssetHolderListKey(holder.AssetCode)
+	itemKey := leveldb.GetAssetHolderItemKey(listKey, holder.Address, holder.AssetId)
+	if holder.Equity == nil || holder.Equity.Sign() <= 0 {
+		return db.removeList(listKey, itemKey)
+	}
+
+	buf, err := rlp.EncodeToBytes(holder)
+	if err != nil {
+		return err
+	}
+	isExist, err := db.LevelDB.Has(itemKey)
+	if err != nil {
+		return err
+	}
+	if isExist {
+		return leveldb.Set(db.LevelDB, itemKey, buf)
+	}
+	return db.appendList(listKey, itemKey, buf)
+}
+
+// GetAssetHolders load the accounts which hold the asset by page. The holders are sorted by address and asset id
+func (db *BizDatabase) GetAssetHolders(code common.Hash, index int, size int) ([]*VAssetHolder, uint32, error) {
+	if (index < 0) || (size > 200) || (size <= 0) {
+		return nil, 0, ErrArgInvalid
+	}
+
+	listKey := leveldb.GetAssetHolderListKey(code)
+	total, err := db.getTxListSize(listKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*VAssetHolder, 0, size)
+	if uint32(index) >= total {
+		return result, total, nil
+	}
+
+	iterator := db.LevelDB.NewIteratorWithPrefix(listKey)
+	for skip := 0; iterator.Next(); skip++ {
+		if skip < index {
+			continue
+		}
+
+		holder := &VAssetHolder{Equity: new(big.Int)}
+		if err := rlp.DecodeBytes(iterator.Value(), holder); err != nil {
+			iterator.Release()
+			return nil, 0, err
+		}
+		result = append(result, holder)
+		if len(result) >= size {
+			break
+		}
+	}
+	iterator.Release()
+	if err := iterator.Error(); err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+// appendTxList append the tx hash to the tx list. It is safe to append a same tx for several times
+func (db *BizDatabase) appendTxList(listKey []byte, height uint32, seq uint32, hash common.Hash) error {
+	return db.appendList(listKey, leveldb.GetTxListItemKey(listKey, height, seq), hash.Bytes())
+}
+
+// getTxAsset returns the asset code and asset id which the transaction operates
+func (db *BizDatabase) getTxAsset(tx *types.Transaction) (common.Hash, common.Hash, error) {
+	switch tx.Type() {
+	case params.CreateAssetTx:
+		return tx.Hash(), common.Hash{}, nil
+	case params.IssueAssetTx:
+		issueAsset, err := types.GetIssueAsset(tx.Data())
+		if err != nil {
+			return common.Hash{}, common.Hash{}, err
+		}
+		return issueAsset.AssetCode, tx.Hash(), nil
+	case params.ReplenishAssetTx:
+		replenishAsset, err := types.GetReplenishAsset(tx.Data())
+		if err != nil {
+			return common.Hash{}, common.Hash{}, err
+		}
+		return replenishAsset.AssetCode, replenishAsset.AssetId, nil
+	case params.ModifyAssetTx:
+		modifyInfo, err := types.GetModifyAssetInfo(tx.Data())
+		if err != nil {
+			return common.Hash{}, common.Hash{}, err
+		}
+		return modifyInfo.AssetCode, common.Hash{}, nil
+	case params.TransferAssetTx:
+		tradingAsset, err := types.GetTradingAsset(tx.Data())
+		if err != nil {
+			return common.Hash{}, common.Hash{}, err
+		}
+		code, err := db.getAssetCodeById(tradingAsset.AssetId)
+		if err != nil {
+			return common.Hash{}, tradingAsset.AssetId, err
+		}
+		return code, tradingAsset.AssetId, nil
+	default:
+		return common.Hash{}, common.Hash{}, nil
+	}
+}
+
+// relatedAddresses returns the accounts which should find the transaction in their tx list
+func relatedAddresses(tx *types.Transaction) []common.Address {
+	result := []common.Address{tx.From()}
+	if to := tx.To(); to != nil && *to != tx.From() {
+		result = append(result, *to)
+	}
+	gasPayer := tx.GasPayer()
+	if gasPayer != tx.From() && (tx.To() == nil || gasPayer != *tx.To()) {
+		result = append(result, gasPayer)
+	}
+	return result
+}
+
+// afterReceipt add the events in receipt to the bloom of block, and index the internal transactions
+func (db *BizDatabase) afterReceipt(key []byte, val []byte) error {
+	var receipt types.Receipt
+	err := rlp.DecodeBytes(val, &receipt)
+	if err != nil {
+		return err
+	}
+
+	if err = db.indexInternalTxs(&receipt); err != nil {
+		return err
+	}
+
+	if len(receipt.Events) <= 0 {
+		return nil
+	}
+
+	bloom, err := db.GetBlockBloom(receipt.Height)
+	if err != nil {
+		return err
+	}
+	bloom.Or(types.EventsBloom(receipt.Events))
+	return leveldb.Set(db.LevelDB, leveldb.GetBlockBloomKey(receipt.Height), bloom.Bytes())
+}
+
+// indexInternalTxs append the internal transactions in receipt to the internal tx lists of related accounts
+func (db *BizDatabase) indexInternalTxs(receipt *types.Receipt) error {
+	for i, internalTx := range receipt.InternalTxs {
+		buf, err := rlp.EncodeToBytes(&VInternalTx{
+			InternalTx: internalTx,
+			TxHash:     receipt.TxHash,
+			BlockHash:  receipt.BlockHash,
+			Height:     receipt.Height,
+		})
+		if err != nil {
+			return err
+		}
+
+		addresses := []common.Address{internalTx.From}
+		if internalTx.To != internalTx.From {
+			addresses = append(addresses, internalTx.To)
+		}
+		for _, addr := range addresses {
+			listKey := leveldb.GetAddrInternalTxListKey(addr)
+			itemKey := leveldb.GetInternalTxItemKey(listKey, receipt.Height, receipt.TxHash, uint32(i))
+			if err = db.appendList(listKey, itemKey, buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendList put the item to the list and update the size of list. It is safe to append a same item for several times
+func (db *BizDatabase) appendList(listKey []byte, itemKey []byte, val []byte) error {
+	isExist, err := db.LevelDB.Has(itemKey)
+	if err != nil {
+		return err
+	}
+
+	if isExist {
+		return nil
+	}
+
+	total, err := db.getTxListSize(listKey)
+	if err != nil {
+		return err
+	}
+
+	err = leveldb.Set(db.LevelDB, itemKey, val)
+	if err != nil {
+		return err
+	}
+
+	return leveldb.Set(db.LevelDB, leveldb.GetTxListSizeKey(listKey), leveldb.EncodeNumber(total+1))
+}
+
+// removeList delete the item from the list and update the size of list. It is safe to remove a same item for several times
+func (db *BizDatabase) removeList(listKey []byte, itemKey []byte) error {
+	isExist, err := db.LevelDB.Has(itemKey)
+	if err != nil {
+		return err
+	}
+
+	if !isExist {
+		return nil
+	}
+
+	total, err := db.getTxListSize(listKey)
+	if err != nil {
+		return err
+	}
+
+	err = db.LevelDB.Delete(itemKey)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		total--
+	}
+	return leveldb.Set(db.LevelDB, leveldb.GetTxListSizeKey(listKey), leveldb.EncodeNumber(total))
+}
+
+// GetInternalTxByAddr load the value transfers made by contracts which are related to the account by page. The transfers are sorted by height
+func (db *BizDatabase) GetInternalTxByAddr(src common.Address, index int, size int) ([]*VInternalTx, uint32, error) {
+	if (index < 0) || (size > 200) || (size <= 0) {
+		return nil, 0, ErrArgInvalid
+	}
+
+	listKey := leveldb.GetAddrInternalTxListKey(src)
+	total, err := db.getTxListSize(listKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*VInternalTx, 0, size)
+	if uint32(index) >= total {
+		return result, total, nil
+	}
+
+	iterator := db.LevelDB.NewIteratorWithPrefix(listKey)
+	for skip := 0; iterator.Next(); skip++ {
+		if skip < index {
+			continue
+		}
+
+		var internalTx VInternalTx
+		if err := rlp.DecodeBytes(iterator.Value(), &internalTx); err != nil {
+			iterator.Release()
+			return nil, 0, err
+		}
+		result = append(result, &internalTx)
+		if len(result) >= size {
+			break
+		}
+	}
+	iterator.Release()
+	if err := iterator.Error(); err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+// GetBlockBloom returns the bloom of all events in a stable block. The bloom is empty if there is no event
+func (db *BizDatabase) GetBlockBloom(height uint32) (types.Bloom, error) {
+	val, err := leveldb.Get(db.LevelDB, leveldb.GetBlockBloomKey(height))
+	if err != nil {
+		return types.Bloom{}, err
+	}
+
+	return types.BytesToBloom(val), nil
+}
+
+// GetEvents search the events in stable blocks between fromHeight and toHeight (both included).
+// The event should be sent by one of addresses, and its topic[i] should be one of topics[i]. Empty conditions match all
+func (db *BizDatabase) GetEvents(fromHeight, toHeight uint32, addresses []common.Address, topics [][]common.Hash) ([]*VEvent, error) {
+	if (fromHeight > toHeight) || (toHeight-fromHeight >= MaxEventsQueryRange) {
+		return nil, ErrArgInvalid
+	}
+
+	result := make([]*VEvent, 0)
+	for height := fromHeight; ; height++ {
+		bloom, err := db.GetBlockBloom(height)
+		if err != nil {
+			return nil, err
+		}
+
+		if bloom.MatchFilter(addresses, topics) {
+			events, err := db.getBlockEvents(height)
+			if err != nil {
+				return nil, err
+			}
+			for _, event := range events {
+				if matchEvent(event.Event, addresses, topics) {
+					result = append(result, event)
+				}
+			}
+		}
+
+		// avoid overflow
+		if height == toHeight {
+			break
+		}
+	}
+	return result, nil
+}
+
+// getBlockEvents load all events in a stable block. The events are sorted by the order of execution
+func (db *BizDatabase) getBlockEvents(height uint32) ([]*VEvent, error) {
+	block, err := db.Reader.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	// the sub transactions in box are executed before the box is finished
+	txs := make(types.Transactions, 0, len(block.Txs))
+	for _, tx := range block.Txs {
+		if tx.Type() == params.BoxTx {
+			box, err := types.GetBox(tx.Data())
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, box.SubTxList...)
+		}
+		txs = append(txs, tx)
+	}
+
+	result := make([]*VEvent, 0)
+	for _, tx := range txs {
+		receipt, err := db.Reader.GetReceipt(tx.Hash())
+		if err == ErrNotExist {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, event := range receipt.Events {
+			result = append(result, &VEvent{
+				Event:     event,
+				BlockHash: receipt.BlockHash,
+				Height:    receipt.Height,
+			})
+		}
+	}
+	return result, nil
+}
+
+// matchEvent checks if the event is sent by one of addresses, and its topic[i] is one of topics[i]
+func matchEvent(event *types.Event, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 && !containsAddress(addresses, event.Address) {
+		return false
+	}
+	if len(topics) > len(event.Topics) {
+		return false
+	}
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			continue // wildcard
+		}
+		if !containsHash(sub, event.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, item := range list {
+		if item == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(list []common.Hash, hash common.Hash) bool {
+	for _, item := range list {
+		if item == hash {
+			return true
+		}
+	}
+	return false
+}
@@ -1 +1,455 @@
 package store
+
+import (
+	"encoding/json"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/params"
+	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
+	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
+	"github.com/LemoFoundationLtd/lemochain-core/store/leveldb"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func createBizTxs() types.Transactions {
+	from := common.HexToAddress("0x10000")
+	to := common.HexToAddress("0x20000")
+	ordinaryTx := types.NewTransaction(from, to, big.NewInt(1), 21000, big.NewInt(1), nil, params.OrdinaryTx, 1, 1538210391, "", "")
+
+	issueData, _ := json.Marshal(&types.IssueAsset{AssetCode: common.HexToHash("0xaaa"), Amount: big.NewInt(100)})
+	issueTx := types.NewTransaction(from, to, big.NewInt(0), 21000, big.NewInt(1), issueData, params.IssueAssetTx, 1, 1538210392, "", "")
+
+	transferData, _ := json.Marshal(&types.TradingAsset{AssetId: issueTx.Hash(), Value: big.NewInt(10)})
+	transferTx := types.NewTransaction(to, from, big.NewInt(0), 21000, big.NewInt(1), transferData, params.TransferAssetTx, 1, 1538210393, "", "")
+
+	subTx := types.NewTransaction(to, common.HexToAddress("0x30000"), big.NewInt(2), 21000, big.NewInt(1), nil, params.OrdinaryTx, 1, 1538210394, "", "")
+	boxData, _ := types.MarshalBoxData(types.Transactions{subTx})
+	boxTx := types.NoReceiverTransaction(to, big.NewInt(0), 21000, big.NewInt(1), boxData, params.BoxTx, 1, 1538210394, "", "")
+	return types.Transactions{ordinaryTx, issueTx, transferTx, boxTx}
+}
+
+func TestBizDatabase_GetTxByHash(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	txs := createBizTxs()
+	block1.SetTxs(txs)
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err := cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+
+	// unstable tx
+	bizDB := cacheChain.GetBizDatabase()
+	_, err = bizDB.GetTxByHash(txs[0].Hash())
+	assert.Equal(t, ErrNotExist, err)
+
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+
+	// normal tx
+	detail, err := bizDB.GetTxByHash(txs[0].Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, block1.Hash(), detail.BlockHash)
+	assert.Equal(t, block1.Height(), detail.Height)
+	assert.Equal(t, txs[0].Hash(), detail.Tx.Hash())
+	assert.Equal(t, common.Hash{}, detail.PHash)
+
+	// asset tx
+	detail, err = bizDB.GetTxByHash(txs[2].Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0xaaa"), detail.AssetCode)
+	assert.Equal(t, txs[1].Hash(), detail.AssetId)
+
+	// sub tx in box
+	box, err := types.GetBox(txs[3].Data())
+	assert.NoError(t, err)
+	subTx := box.SubTxList[0]
+	detail, err = bizDB.GetTxByHash(subTx.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, subTx.Hash(), detail.Tx.Hash())
+	assert.Equal(t, txs[3].Hash(), detail.PHash)
+
+	// the tx whose asset can't be parsed doesn't break the index of later txs
+	block2 := GetBlock2()
+	unknownTx := types.NewTransaction(common.HexToAddress("0x40000"), common.HexToAddress("0x50000"), big.NewInt(0), 21000, big.NewInt(1), []byte("invalid"), params.TransferAssetTx, 1, 1538210395, "", "")
+	laterTx := types.NewTransaction(common.HexToAddress("0x40000"), common.HexToAddress("0x50000"), big.NewInt(1), 21000, big.NewInt(1), nil, params.OrdinaryTx, 1, 1538210396, "", "")
+	block2.SetTxs(types.Transactions{unknownTx, laterTx})
+	assert.NoError(t, cacheChain.SetBlock(block2.Hash(), block2))
+	_, err = cacheChain.SetStableBlock(block2.Hash())
+	assert.NoError(t, err)
+	detail, err = bizDB.GetTxByHash(unknownTx.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, common.Hash{}, detail.AssetCode)
+	detail, err = bizDB.GetTxByHash(laterTx.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, laterTx.Hash(), detail.Tx.Hash())
+}
+
+func TestBizDatabase_GetTxByAddr(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	txs := createBizTxs()
+	block1.SetTxs(txs)
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err := cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+	bizDB := cacheChain.GetBizDatabase()
+
+	// invalid page
+	_, _, err = bizDB.GetTxByAddr(common.HexToAddress("0x10000"), -1, 10)
+	assert.Equal(t, ErrArgInvalid, err)
+	_, _, err = bizDB.GetTxByAddr(common.HexToAddress("0x10000"), 0, 0)
+	assert.Equal(t, ErrArgInvalid, err)
+
+	// 0x20000 is related to all txs
+	result, total, err := bizDB.GetTxByAddr(common.HexToAddress("0x20000"), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), total)
+	assert.Len(t, result, 5)
+	assert.Equal(t, txs[0].Hash(), result[0].Tx.Hash())
+	assert.Equal(t, txs[3].Hash(), result[3].Tx.Hash())
+	assert.Equal(t, txs[3].Hash(), result[4].PHash)
+	assert.Equal(t, block1.Time(), result[0].PackageTime)
+
+	// paging
+	result, total, err = bizDB.GetTxByAddr(common.HexToAddress("0x20000"), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), total)
+	assert.Len(t, result, 2)
+	assert.Equal(t, txs[1].Hash(), result[0].Tx.Hash())
+	assert.Equal(t, txs[2].Hash(), result[1].Tx.Hash())
+	result, total, err = bizDB.GetTxByAddr(common.HexToAddress("0x20000"), 5, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), total)
+	assert.Len(t, result, 0)
+
+	// sub tx receiver
+	result, total, err = bizDB.GetTxByAddr(common.HexToAddress("0x30000"), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), total)
+
+	// filter by asset
+	result, total, err = bizDB.GetTxByAssetCode(common.HexToAddress("0x10000"), common.HexToHash("0xaaa"), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+	assert.Equal(t, txs[1].Hash(), result[0].Tx.Hash())
+	assert.Equal(t, txs[2].Hash(), result[1].Tx.Hash())
+	result, total, err = bizDB.GetTxByAssetId(common.HexToAddress("0x10000"), txs[1].Hash(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+
+	// index the same block again
+	err = cacheChain.BizDB.indexTx(txs[0], &txPosition{BlockHash: block1.Hash(), Height: block1.Height()}, 0)
+	assert.NoError(t, err)
+	_, total, err = bizDB.GetTxByAddr(common.HexToAddress("0x20000"), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), total)
+}
+
+func TestBizDatabase_GetEvents(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	txs := createBizTxs()
+	block1.SetTxs(txs)
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err := cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+
+	contract := common.HexToAddress("0x50000")
+	transferTopic := common.HexToHash("0xddf252ad")
+	otherTopic := common.HexToHash("0x8c5be1e5")
+	receipts := make(types.Receipts, 0)
+	for _, tx := range txs {
+		receipt := types.NewReceipt(tx.Hash(), nil, 21000, 21000)
+		receipt.Events = append(receipt.Events, &types.Event{Address: contract, Topics: []common.Hash{transferTopic, common.HexToHash("0x10000")}, Data: []byte{}, TxHash: tx.Hash()})
+		receipts = append(receipts, receipt)
+	}
+	receipts[1].Events[0].Topics = []common.Hash{otherTopic}
+	assert.NoError(t, cacheChain.SetReceipts(block1.Hash(), receipts))
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+	bizDB := cacheChain.GetBizDatabase()
+
+	// bloom
+	bloom, err := bizDB.GetBlockBloom(0)
+	assert.NoError(t, err)
+	assert.True(t, bloom.IsEmpty())
+	bloom, err = bizDB.GetBlockBloom(1)
+	assert.NoError(t, err)
+	assert.True(t, bloom.Test(contract.Bytes()))
+	assert.True(t, bloom.Test(otherTopic.Bytes()))
+
+	// invalid range
+	_, err = bizDB.GetEvents(1, 0, nil, nil)
+	assert.Equal(t, ErrArgInvalid, err)
+	_, err = bizDB.GetEvents(0, MaxEventsQueryRange, nil, nil)
+	assert.Equal(t, ErrArgInvalid, err)
+
+	// all events
+	events, err := bizDB.GetEvents(0, 1, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, block1.Hash(), events[0].BlockHash)
+	assert.Equal(t, block1.Height(), events[0].Height)
+	assert.Equal(t, txs[0].Hash(), events[0].Event.TxHash)
+
+	// filter by address and topics
+	events, err = bizDB.GetEvents(0, 1, []common.Address{contract}, [][]common.Hash{{transferTopic}})
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	events, err = bizDB.GetEvents(0, 1, nil, [][]common.Hash{nil, {common.HexToHash("0x10000")}})
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	events, err = bizDB.GetEvents(0, 1, nil, [][]common.Hash{{otherTopic, transferTopic}})
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+	events, err = bizDB.GetEvents(0, 1, []common.Address{common.HexToAddress("0x60000")}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+	events, err = bizDB.GetEvents(0, 0, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+}
+
+func TestBizDatabase_GetInternalTxByAddr(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	txs := createBizTxs()
+	block1.SetTxs(txs)
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err := cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+
+	contract := common.HexToAddress("0x50000")
+	receiver := common.HexToAddress("0x60000")
+	receipts := make(types.Receipts, 0)
+	for _, tx := range txs {
+		receipts = append(receipts, types.NewReceipt(tx.Hash(), nil, 21000, 21000))
+	}
+	receipts[0].InternalTxs = []*types.InternalTx{
+		{From: contract, To: receiver, Value: big.NewInt(1), Depth: 1},
+		{From: contract, To: contract, Value: big.NewInt(2), Depth: 2},
+	}
+	receipts[2].InternalTxs = []*types.InternalTx{
+		{From: receiver, To: contract, Value: big.NewInt(3), Depth: 1},
+	}
+	assert.NoError(t, cacheChain.SetReceipts(block1.Hash(), receipts))
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+	bizDB := cacheChain.GetBizDatabase()
+
+	// invalid page
+	_, _, err = bizDB.GetInternalTxByAddr(contract, -1, 10)
+	assert.Equal(t, ErrArgInvalid, err)
+	_, _, err = bizDB.GetInternalTxByAddr(contract, 0, 0)
+	assert.Equal(t, ErrArgInvalid, err)
+
+	// the transfer to self is indexed only once
+	list, total, err := bizDB.GetInternalTxByAddr(contract, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), total)
+	assert.Len(t, list, 3)
+	for _, item := range list {
+		assert.Equal(t, block1.Hash(), item.BlockHash)
+		assert.Equal(t, block1.Height(), item.Height)
+	}
+
+	list, total, err = bizDB.GetInternalTxByAddr(receiver, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+	assert.Len(t, list, 1)
+
+	list, total, err = bizDB.GetInternalTxByAddr(receiver, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+	assert.Len(t, list, 1)
+
+	// not exist
+	list, total, err = bizDB.GetInternalTxByAddr(common.HexToAddress("0x70000"), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), total)
+	assert.Len(t, list, 0)
+}
+
+// testEquityLog is registered in store tests to decode the equity change logs, because the real log types are defined in account package
+const testEquityLog = types.ChangeLogType(100)
+
+func init() {
+	decodeEquity := func(s *rlp.Stream) (interface{}, error) {
+		// the deleted equity
+		if _, size, _ := s.Kind(); size <= 0 {
+			var result interface{}
+			return nil, s.Decode(&result)
+		}
+		result := &types.AssetEquity{Equity: new(big.Int)}
+		err := s.Decode(result)
+		return result, err
+	}
+	decodeHash := func(s *rlp.Stream) (interface{}, error) {
+		var result []byte
+		err := s.Decode(&result)
+		return common.BytesToHash(result), err
+	}
+	types.RegisterChangeLog(testEquityLog, "EquityLog", decodeEquity, decodeHash, nil, nil)
+	EquityLogType = testEquityLog
+}
+
+func newTestEquityLog(addr common.Address, version uint32, code, id common.Hash, equity int64) *types.ChangeLog {
+	return &types.ChangeLog{
+		LogType: testEquityLog,
+		Address: addr,
+		Version: version,
+		NewVal:  &types.AssetEquity{AssetCode: code, AssetId: id, Equity: big.NewInt(equity)},
+		Extra:   id,
+	}
+}
+
+func TestBizDatabase_GetAssetHolders(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	code := common.HexToHash("0xaaa")
+	id1 := common.HexToHash("0x111")
+	id2 := common.HexToHash("0x222")
+	addr1 := common.HexToAddress("0x10000")
+	addr2 := common.HexToAddress("0x20000")
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	block1.SetChangeLogs([]*types.ChangeLog{
+		newTestEquityLog(addr2, 1, code, id1, 100),
+		newTestEquityLog(addr2, 2, code, id1, 90),
+		newTestEquityLog(addr1, 1, code, id1, 10),
+		newTestEquityLog(addr1, 2, code, id2, 20),
+	})
+	block2 := GetBlock2()
+	block2.SetChangeLogs([]*types.ChangeLog{
+		newTestEquityLog(addr1, 3, code, id2, 0),
+	})
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err := cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+	bizDB := cacheChain.GetBizDatabase()
+
+	// invalid page
+	_, _, err = bizDB.GetAssetHolders(code, -1, 10)
+	assert.Equal(t, ErrArgInvalid, err)
+	_, _, err = bizDB.GetAssetHolders(code, 0, 201)
+	assert.Equal(t, ErrArgInvalid, err)
+
+	// the latest equity is kept
+	holders, total, err := bizDB.GetAssetHolders(code, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), total)
+	assert.Equal(t, []*VAssetHolder{
+		{Address: addr1, AssetCode: code, AssetId: id1, Equity: big.NewInt(10)},
+		{Address: addr1, AssetCode: code, AssetId: id2, Equity: big.NewInt(20)},
+		{Address: addr2, AssetCode: code, AssetId: id1, Equity: big.NewInt(90)},
+	}, holders)
+
+	// paging
+	holders, total, err = bizDB.GetAssetHolders(code, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), total)
+	assert.Len(t, holders, 1)
+	assert.Equal(t, addr2, holders[0].Address)
+
+	// the holder is removed if equity is zero
+	assert.NoError(t, cacheChain.SetBlock(block2.Hash(), block2))
+	_, err = cacheChain.SetStableBlock(block2.Hash())
+	assert.NoError(t, err)
+	holders, total, err = bizDB.GetAssetHolders(code, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+	assert.Len(t, holders, 2)
+	assert.Equal(t, id1, holders[0].AssetId)
+
+	// index the same logs again
+	assert.NoError(t, cacheChain.BizDB.indexEquities(block1.ChangeLogs))
+	assert.NoError(t, cacheChain.BizDB.indexEquities(block2.ChangeLogs))
+	_, total, err = bizDB.GetAssetHolders(code, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+
+	// the holder is removed if equity is deleted
+	assert.NoError(t, leveldb.Set(cacheChain.BizDB.LevelDB, leveldb.GetAssetIdCodeKey(id1), code.Bytes()))
+	block3 := GetBlock3()
+	block3.SetChangeLogs([]*types.ChangeLog{
+		{LogType: testEquityLog, Address: addr2, Version: 3, NewVal: nil, Extra: id1},
+	})
+	assert.NoError(t, cacheChain.SetBlock(block3.Hash(), block3))
+	_, err = cacheChain.SetStableBlock(block3.Hash())
+	assert.NoError(t, err)
+	holders, total, err = bizDB.GetAssetHolders(code, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), total)
+	assert.Equal(t, []*VAssetHolder{
+		{Address: addr1, AssetCode: code, AssetId: id1, Equity: big.NewInt(10)},
+	}, holders)
+
+	// not exist
+	holders, total, err = bizDB.GetAssetHolders(common.HexToHash("0xbbb"), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), total)
+	assert.Len(t, holders, 0)
+}
+
+func TestBizDatabase_GetAssetTransfers(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	txs := createBizTxs()
+	block1.SetTxs(txs)
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err := cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+	bizDB := cacheChain.GetBizDatabase()
+
+	_, _, err = bizDB.GetAssetTransfers(txs[1].Hash(), 0, 0)
+	assert.Equal(t, ErrArgInvalid, err)
+
+	// issue and transfer
+	result, total, err := bizDB.GetAssetTransfers(txs[1].Hash(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+	assert.Equal(t, txs[1].Hash(), result[0].Tx.Hash())
+	assert.Equal(t, txs[2].Hash(), result[1].Tx.Hash())
+	assert.Equal(t, common.HexToHash("0xaaa"), result[1].AssetCode)
+
+	// not exist
+	result, total, err = bizDB.GetAssetTransfers(txs[0].Hash(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), total)
+	assert.Len(t, result, 0)
+}
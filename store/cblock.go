@@ -12,6 +12,7 @@ type CBlock struct {
 	AccountTrieDB   *AccountTrieDB
 	CandidateTrieDB *CandidateTrieDB
 	Top             *VoteTop
+	Receipts        types.Receipts // the execution results of txs in block. They will be saved when the block become stable
 	Parent          *CBlock
 	Children        []*CBlock
 }
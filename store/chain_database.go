@@ -239,17 +239,17 @@ func (database *ChainDatabase) blockCommit(hash common.Hash) error {
 	batch := database.Beansdb.NewBatch()
 
 	// store block
-	buf, err := rlp.EncodeToBytes(cItem.Block)
+	blockBuf, err := rlp.EncodeToBytes(cItem.Block)
 	if err != nil {
 		return err
 	}
 
-	batch.Put(leveldb.ItemFlagBlock, hash.Bytes(), buf)
+	batch.Put(leveldb.ItemFlagBlock, hash.Bytes(), blockBuf)
 	batch.Put(leveldb.ItemFlagBlockHeight, leveldb.EncodeNumber(cItem.Block.Height()), hash.Bytes())
 
 	// store account
 	decode := func(account *types.AccountData, batch Batch) error {
-		buf, err = rlp.EncodeToBytes(account)
+		buf, err := rlp.EncodeToBytes(account)
 		if err != nil {
 			return err
 		} else {
@@ -292,11 +292,34 @@ func (database *ChainDatabase) blockCommit(hash common.Hash) error {
 		return err
 	}
 
+	// store receipts
+	receiptBufs := make([][]byte, len(cItem.Receipts))
+	for index, receipt := range cItem.Receipts {
+		buf, err := rlp.EncodeToBytes(receipt)
+		if err != nil {
+			return err
+		}
+		batch.Put(leveldb.ItemFlagReceipt, receipt.TxHash.Bytes(), buf)
+		receiptBufs[index] = buf
+	}
+
 	err = database.Beansdb.Commit(batch)
 	if err != nil {
 		return err
 	}
 
+	// the business index is only used for query, so it should not break the block commit
+	err = database.BizDB.AfterCommit(leveldb.ItemFlagBlock, hash.Bytes(), blockBuf)
+	if err != nil {
+		log.Errorf("index stable block for business query fail. height: %d, err: %v", cItem.Block.Height(), err)
+	}
+	for index, receipt := range cItem.Receipts {
+		err = database.BizDB.AfterCommit(leveldb.ItemFlagReceipt, receipt.TxHash.Bytes(), receiptBufs[index])
+		if err != nil {
+			log.Errorf("index receipt for business query fail. tx: %s, err: %v", receipt.TxHash.Hex(), err)
+		}
+	}
+
 	candidates := cItem.filterCandidates(accounts)
 	// 注意这里即使是为注销候选节点不能删除记录，这里保存进去只是修改票数为0，因为在退还候选节点押金的地方要拉取所有的候选节点来判断注销的候选节点是否没有退还押金。
 	return commitContext(cItem.Block, candidates)
@@ -516,6 +539,39 @@ func (database *ChainDatabase) SetBlock(hash common.Hash, block *types.Block) er
 	return nil
 }
 
+// SetReceipts attaches the transactions' execution results to an unstable block. They will be saved with the block when it become stable
+func (database *ChainDatabase) SetReceipts(hash common.Hash, receipts types.Receipts) error {
+	database.RW.Lock()
+	defer database.RW.Unlock()
+
+	cItem := database.UnConfirmBlocks[hash]
+	if (cItem == nil) || (cItem.Block == nil) {
+		log.Errorf("set receipts error:the block is not exist. hash: %s", hash.Hex())
+		return ErrNotExist
+	}
+
+	for _, receipt := range receipts {
+		receipt.BlockHash = hash
+		receipt.Height = cItem.Block.Height()
+	}
+	cItem.Receipts = receipts
+	return nil
+}
+
+// GetReceipt loads the execution result of a transaction in stable block
+func (database *ChainDatabase) GetReceipt(txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := UtilsGetReceipt(database.Beansdb, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt == nil {
+		return nil, ErrNotExist
+	} else {
+		return receipt, nil
+	}
+}
+
 func (database *ChainDatabase) appendConfirm(block *types.Block, confirms []types.SignData) {
 	if (block == nil) || (confirms == nil) {
 		return
@@ -643,6 +699,10 @@ func (database *ChainDatabase) GetAccount(addr common.Address) (*types.AccountDa
 	}
 }
 
+func (database *ChainDatabase) GetBizDatabase() BizDb {
+	return database.BizDB
+}
+
 func (database *ChainDatabase) GetTrieDatabase() *TrieDatabase {
 	return NewTrieDatabase(database.Beansdb)
 }
@@ -795,6 +855,14 @@ func (database *ChainDatabase) SerializeForks(currentHash common.Hash) string {
 	return "Print forks\n" + forkStr
 }
 
+// GetForks returns every branch from the child of the stable block to the unstable leaf block
+func (database *ChainDatabase) GetForks(currentHash common.Hash) []*Fork {
+	database.RW.RLock()
+	defer database.RW.RUnlock()
+
+	return CollectForks(database.UnConfirmBlocks, database.LastConfirm, currentHash)
+}
+
 func (database *ChainDatabase) Close() error {
 	database.Beansdb.Close()
 
@@ -653,3 +653,36 @@ func TestChainDatabase_CandidatesRanking(t *testing.T) {
 	assert.Equal(t, uint32(count), total)
 	cacheChain.Close()
 }
+
+func TestChainDatabase_SetReceipts(t *testing.T) {
+	ClearData()
+	cacheChain := NewChainDataBase(GetStorePath())
+	defer cacheChain.Close()
+
+	block0 := GetBlock0()
+	block1 := GetBlock1()
+	receipt := types.NewReceipt(common.HexToHash("0x11"), nil, 21000, 21000)
+
+	// not exist block
+	err := cacheChain.SetReceipts(block1.Hash(), types.Receipts{receipt})
+	assert.Equal(t, ErrNotExist, err)
+
+	assert.NoError(t, cacheChain.SetBlock(block0.Hash(), block0))
+	_, err = cacheChain.SetStableBlock(block0.Hash())
+	assert.NoError(t, err)
+	assert.NoError(t, cacheChain.SetBlock(block1.Hash(), block1))
+	err = cacheChain.SetReceipts(block1.Hash(), types.Receipts{receipt})
+	assert.NoError(t, err)
+	assert.Equal(t, block1.Hash(), receipt.BlockHash)
+	assert.Equal(t, block1.Height(), receipt.Height)
+
+	// unstable receipt
+	_, err = cacheChain.GetReceipt(receipt.TxHash)
+	assert.Equal(t, ErrNotExist, err)
+
+	_, err = cacheChain.SetStableBlock(block1.Hash())
+	assert.NoError(t, err)
+	result, err := cacheChain.GetReceipt(receipt.TxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, receipt, result)
+}
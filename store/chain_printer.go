@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/hexutil"
 	"github.com/LemoFoundationLtd/lemochain-core/common/log"
 	"sort"
 	"strconv"
@@ -13,6 +14,29 @@ import (
 // The bytes length of prefix hash to show
 const hashLength = 3
 
+//go:generate gencodec -type ForkBlock --field-override forkBlockMarshaling -out gen_fork_block_json.go
+
+// ForkBlock is the summary of an unstable block in fork tree
+type ForkBlock struct {
+	Hash         common.Hash    `json:"hash" gencodec:"required"`
+	Height       uint32         `json:"height" gencodec:"required"`
+	MinerAddress common.Address `json:"minerAddress" gencodec:"required"`
+	// ConfirmCount is the count of confirms in block. The miner's signature is not included
+	ConfirmCount uint32 `json:"confirmCount" gencodec:"required"`
+}
+
+type forkBlockMarshaling struct {
+	Height       hexutil.Uint32
+	ConfirmCount hexutil.Uint32
+}
+
+// Fork is a branch from the child of the stable block to an unstable leaf block
+type Fork struct {
+	Blocks []*ForkBlock `json:"blocks"`
+	// IsCurrent is true if the current head block is in this branch
+	IsCurrent bool `json:"isCurrent"`
+}
+
 type cbTable struct {
 	// It's a fork path table. Every row is a fork, and every blocks in a column has same height. The first item in each row is the root block of the fork
 	Rows [][]*CBlock
@@ -217,3 +241,40 @@ func SerializeForks(unconfirmedBlocks map[common.Hash]*CBlock, currentHash commo
 	table.Sort(0, len(table.Rows), 0)
 	return table.String()
 }
+
+// CollectForks collects every branch from the child of root to the leaf block. The branches are in the same order as SerializeForks
+func CollectForks(unconfirmedBlocks map[common.Hash]*CBlock, root *CBlock, currentHash common.Hash) []*Fork {
+	leaves := make([]*CBlock, 0, len(unconfirmedBlocks))
+	for _, block := range unconfirmedBlocks {
+		if len(block.Children) == 0 {
+			leaves = append(leaves, block)
+		}
+	}
+	if len(leaves) == 0 {
+		return []*Fork{}
+	}
+
+	table := newCbTable(leaves, currentHash)
+	table.Sort(0, len(table.Rows), 0)
+	forks := make([]*Fork, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		fork := &Fork{Blocks: make([]*ForkBlock, 0, len(row))}
+		for _, cBlock := range row {
+			if cBlock == root {
+				continue
+			}
+			hash := cBlock.Block.Hash()
+			fork.Blocks = append(fork.Blocks, &ForkBlock{
+				Hash:         hash,
+				Height:       cBlock.Block.Height(),
+				MinerAddress: cBlock.Block.MinerAddress(),
+				ConfirmCount: uint32(len(cBlock.Block.Confirms)),
+			})
+			if hash == currentHash {
+				fork.IsCurrent = true
+			}
+		}
+		forks = append(forks, fork)
+	}
+	return forks
+}
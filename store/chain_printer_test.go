@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/LemoFoundationLtd/lemochain-core/chain/types"
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
 )
 
 // makeForkBlocks make blocks and setup the tree struct like this:
@@ -61,3 +63,37 @@ func ExampleSerializeForks() {
 	//                         │           └[102]6490a0─[103]db4799 <-Current
 	//                         └[101]29d8a5─[102]379da9
 }
+
+func TestCollectForks(t *testing.T) {
+	blocks := makeForkBlocks()
+	// the root block is stable, so it is not in the unconfirmed map
+	blockMap := make(map[common.Hash]*CBlock, len(blocks))
+	for _, block := range blocks[1:] {
+		blockMap[block.Block.Hash()] = block
+	}
+
+	// no fork
+	assert.Empty(t, CollectForks(map[common.Hash]*CBlock{}, blocks[0], common.Hash{}))
+
+	forks := CollectForks(blockMap, blocks[0], blocks[9].Block.Hash())
+	assert.Equal(t, 5, len(forks))
+	forkByLeaf := make(map[common.Hash]*Fork)
+	for _, fork := range forks {
+		assert.Equal(t, blocks[1].Block.Hash(), fork.Blocks[0].Hash)
+		forkByLeaf[fork.Blocks[len(fork.Blocks)-1].Hash] = fork
+	}
+	currentFork := forkByLeaf[blocks[9].Block.Hash()]
+	assert.True(t, currentFork.IsCurrent)
+	assert.Equal(t, 4, len(currentFork.Blocks))
+	for i, index := range []int{1, 4, 7, 9} {
+		assert.Equal(t, blocks[index].Block.Hash(), currentFork.Blocks[i].Hash)
+		assert.Equal(t, blocks[index].Block.Height(), currentFork.Blocks[i].Height)
+	}
+	for _, index := range []int{2, 5, 6, 8} {
+		assert.False(t, forkByLeaf[blocks[index].Block.Hash()].IsCurrent)
+	}
+	assert.Equal(t, 3, len(forkByLeaf[blocks[6].Block.Hash()].Blocks))
+
+	// same order as SerializeForks
+	assert.Equal(t, forks, CollectForks(blockMap, blocks[0], blocks[9].Block.Hash()))
+}
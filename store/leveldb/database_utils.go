@@ -31,7 +31,8 @@ var (
 	ItemFlagKV          = uint32(7)
 	ItemFlagAssetCode   = uint32(8)
 	ItemFlagAssetId     = uint32(9)
-	ItemFlagStop        = uint32(10)
+	ItemFlagReceipt     = uint32(10)
+	ItemFlagStop        = uint32(11)
 )
 
 var (
@@ -62,10 +63,24 @@ var (
 	KVPrefix = []byte("KV")
 	KVSuffix = []byte("kv")
 
+	ReceiptPrefix = []byte("RC")
+	ReceiptSuffix = []byte("rc")
+
 	BitCaskCurrentOffsetPrefix = []byte("OFFSET")
 	BitCaskCurrentOffsetSuffix = []byte("offset")
 
 	StableBlockKey = []byte("LEMO-CURRENT-BLOCK")
+
+	// business indexes which are only used for query
+	AddrTxPrefix          = []byte("BA") // AddrTxPrefix + address + height + seq -> tx hash
+	AddrAssetCodeTxPrefix = []byte("BC") // AddrAssetCodeTxPrefix + address + asset code + height + seq -> tx hash
+	AddrAssetIdTxPrefix   = []byte("BI") // AddrAssetIdTxPrefix + address + asset id + height + seq -> tx hash
+	TxListSizePrefix      = []byte("BN") // TxListSizePrefix + tx list key -> the count of txs in list
+	AssetIdCodePrefix     = []byte("BD") // AssetIdCodePrefix + asset id -> asset code
+	BlockBloomPrefix      = []byte("BL") // BlockBloomPrefix + height -> the bloom of events in block
+	AddrInternalTxPrefix  = []byte("BX") // AddrInternalTxPrefix + address + height + tx hash + index -> internal tx
+	AssetHolderPrefix     = []byte("BE") // AssetHolderPrefix + asset code + address + asset id -> the equity of holder
+	AssetIdTxPrefix       = []byte("BF") // AssetIdTxPrefix + asset id + height + seq -> tx hash
 )
 
 func CheckItemFlag(flg uint32) bool {
@@ -105,6 +120,8 @@ func Key(flag uint32, key []byte) []byte {
 		return append(append(AssetCodePrefix, key...), AssetCodeSuffix...)
 	case ItemFlagAssetId:
 		return append(append(AssetIdPrefix, key...), AssetIdSuffix...)
+	case ItemFlagReceipt:
+		return append(append(ReceiptPrefix, key...), ReceiptSuffix...)
 	default:
 		return key
 	}
@@ -155,6 +172,10 @@ func EncodeNumber(height uint32) []byte {
 	return enc
 }
 
+func DecodeNumber(buf []byte) uint32 {
+	return binary.BigEndian.Uint32(buf)
+}
+
 func GetCurrentPos(db DatabaseReader, index int) (uint32, error) {
 	key := append(append(BitCaskCurrentOffsetPrefix, []byte(strconv.Itoa(index))...), BitCaskCurrentOffsetSuffix...)
 	data, err := db.Get(key)
@@ -197,6 +218,74 @@ func SetCurrentBlock(db DatabasePutter, hash common.Hash) error {
 	return db.Put(StableBlockKey, hash.Bytes())
 }
 
+// concatKey concat the parts to a new key. It won't modify any of the parts
+func concatKey(parts ...[]byte) []byte {
+	size := 0
+	for _, part := range parts {
+		size += len(part)
+	}
+	key := make([]byte, 0, size)
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+func GetTxIndexKey(hash common.Hash) []byte {
+	return Key(ItemFlagTxIndex, hash.Bytes())
+}
+
+func GetAddrTxListKey(addr common.Address) []byte {
+	return concatKey(AddrTxPrefix, addr.Bytes())
+}
+
+func GetAddrAssetCodeTxListKey(addr common.Address, code common.Hash) []byte {
+	return concatKey(AddrAssetCodeTxPrefix, addr.Bytes(), code.Bytes())
+}
+
+func GetAddrAssetIdTxListKey(addr common.Address, id common.Hash) []byte {
+	return concatKey(AddrAssetIdTxPrefix, addr.Bytes(), id.Bytes())
+}
+
+// GetTxListItemKey returns the key of an item in tx list. The items are sorted by height and seq
+func GetTxListItemKey(listKey []byte, height uint32, seq uint32) []byte {
+	return concatKey(listKey, EncodeNumber(height), EncodeNumber(seq))
+}
+
+func GetAddrInternalTxListKey(addr common.Address) []byte {
+	return concatKey(AddrInternalTxPrefix, addr.Bytes())
+}
+
+// GetInternalTxItemKey returns the key of an item in internal tx list. The items are sorted by height
+func GetInternalTxItemKey(listKey []byte, height uint32, txHash common.Hash, index uint32) []byte {
+	return concatKey(listKey, EncodeNumber(height), txHash.Bytes(), EncodeNumber(index))
+}
+
+func GetAssetHolderListKey(code common.Hash) []byte {
+	return concatKey(AssetHolderPrefix, code.Bytes())
+}
+
+// GetAssetHolderItemKey returns the key of an item in asset holder list. The items are sorted by address and asset id
+func GetAssetHolderItemKey(listKey []byte, addr common.Address, id common.Hash) []byte {
+	return concatKey(listKey, addr.Bytes(), id.Bytes())
+}
+
+func GetAssetIdTxListKey(id common.Hash) []byte {
+	return concatKey(AssetIdTxPrefix, id.Bytes())
+}
+
+func GetTxListSizeKey(listKey []byte) []byte {
+	return concatKey(TxListSizePrefix, listKey)
+}
+
+func GetAssetIdCodeKey(id common.Hash) []byte {
+	return concatKey(AssetIdCodePrefix, id.Bytes())
+}
+
+func GetBlockBloomKey(height uint32) []byte {
+	return concatKey(BlockBloomPrefix, EncodeNumber(height))
+}
+
 func Set(db DatabasePutter, key []byte, val []byte) error {
 	return db.Put(key, val)
 }
@@ -16,6 +16,9 @@ type ChainDB interface {
 	GetUnConfirmByHeight(height uint32, leafBlockHash common.Hash) (*types.Block, error)
 	IterateUnConfirms(fn func(*types.Block))
 
+	SetReceipts(hash common.Hash, receipts types.Receipts) error
+	GetReceipt(txHash common.Hash) (*types.Receipt, error)
+
 	GetConfirms(hash common.Hash) ([]types.SignData, error)
 	SetConfirms(hash common.Hash, pack []types.SignData) (*types.Block, error)
 
@@ -24,6 +27,7 @@ type ChainDB interface {
 
 	GetAccount(addr common.Address) (*types.AccountData, error)
 
+	GetBizDatabase() store.BizDb
 	GetTrieDatabase() *store.TrieDatabase
 	GetActDatabase(hash common.Hash) (*store.AccountTrieDB, error)
 
@@ -33,11 +37,13 @@ type ChainDB interface {
 	CandidatesRanking(hash common.Hash, voteLogs types.ChangeLogSlice)
 	GetCandidatesTop(hash common.Hash) []*store.Candidate
 	GetAllCandidates() ([]common.Address, error)
+	GetCandidatesPage(index int, size int) ([]common.Address, uint32, error)
 
 	GetAssetID(id common.Hash) (common.Address, error)
 	GetAssetCode(code common.Hash) (common.Address, error)
 
 	SerializeForks(currentHash common.Hash) string
+	GetForks(currentHash common.Hash) []*store.Fork
 
 	Close() error
 }
@@ -22,6 +22,9 @@ import (
 	"github.com/LemoFoundationLtd/lemochain-core/store/leveldb"
 
 	"github.com/LemoFoundationLtd/lemochain-core/common"
+	"github.com/LemoFoundationLtd/lemochain-core/common/crypto"
+	"github.com/LemoFoundationLtd/lemochain-core/common/log"
+	"github.com/LemoFoundationLtd/lemochain-core/common/rlp"
 	"github.com/LemoFoundationLtd/lemochain-core/store"
 )
 
@@ -32,70 +35,76 @@ import (
 // If the trie does not contain a value for key, the returned proof contains all
 // nodes of the longest existing prefix of the key (at least the root node), ending
 // with the node that proves the absence of the key.
-// func (t *Trie) Prove(key []byte, fromLevel uint, proofDb store.Putter) error {
-// 	// Collect all nodes on the path to key.
-// 	key = keybytesToHex(key)
-// 	nodes := []node{}
-// 	tn := t.root
-// 	for len(key) > 0 && tn != nil {
-// 		switch n := tn.(type) {
-// 		case *shortNode:
-// 			if len(key) < len(n.Key) || !bytes.Equal(n.Key, key[:len(n.Key)]) {
-// 				// The trie doesn't contain the key.
-// 				tn = nil
-// 			} else {
-// 				tn = n.Val
-// 				key = key[len(n.Key):]
-// 			}
-// 			nodes = append(nodes, n)
-// 		case *fullNode:
-// 			tn = n.Children[key[0]]
-// 			key = key[1:]
-// 			nodes = append(nodes, n)
-// 		case hashNode:
-// 			var err error
-// 			tn, err = t.resolveHash(n, nil)
-// 			if err != nil {
-// 				log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
-// 				return err
-// 			}
-// 		default:
-// 			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
-// 		}
-// 	}
-// 	hasher := newHasher(0, 0, nil)
-// 	for i, n := range nodes {
-// 		// Don't bother checking for errors here since hasher panics
-// 		// if encoding doesn't work and we're not writing to any database.
-// 		n, _, _ = hasher.hashChildren(n, nil)
-// 		hn, _ := hasher.store(n, nil, false)
-// 		if hash, ok := hn.(hashNode); ok || i == 0 {
-// 			// If the node's database encoding is a hash (or is the
-// 			// root node), it becomes a proof element.
-// 			if fromLevel > 0 {
-// 				fromLevel--
-// 			} else {
-// 				enc, _ := rlp.EncodeToBytes(n)
-// 				if !ok {
-// 					hash = crypto.Keccak256(enc)
-// 				}
-// 				proofDb.Put(hash, enc)
-// 			}
-// 		}
-// 	}
-// 	return nil
-// }
+func (t *Trie) Prove(key []byte, fromLevel uint, proofDb store.Putter) error {
+	// Collect all nodes on the path to key.
+	key = keybytesToHex(key)
+	nodes := []node{}
+	tn := t.root
+	for len(key) > 0 && tn != nil {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(key) < len(n.Key) || !bytes.Equal(n.Key, key[:len(n.Key)]) {
+				// The trie doesn't contain the key.
+				tn = nil
+			} else {
+				tn = n.Val
+				key = key[len(n.Key):]
+			}
+			nodes = append(nodes, n)
+		case *fullNode:
+			tn = n.Children[key[0]]
+			key = key[1:]
+			nodes = append(nodes, n)
+		case hashNode:
+			var err error
+			tn, err = t.resolveHash(n, nil)
+			if err != nil {
+				log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+				return err
+			}
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
+		}
+	}
+	hasher := newHasher(0, 0, nil)
+	defer returnHasherToPool(hasher)
+	for i, n := range nodes {
+		// Don't bother checking for errors here since hasher panics
+		// if encoding doesn't work and we're not writing to any database.
+		n, _, _ = hasher.hashChildren(n, nil)
+		hn, _ := hasher.store(n, nil, false)
+		if hash, ok := hn.(hashNode); ok || i == 0 {
+			// If the node's database encoding is a hash (or is the
+			// root node), it becomes a proof element.
+			if fromLevel > 0 {
+				fromLevel--
+			} else {
+				enc, _ := rlp.EncodeToBytes(n)
+				if !ok {
+					hash = crypto.Keccak256(enc)
+				}
+				if err := proofDb.Put(leveldb.ItemFlagTrie, hash, enc); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
 
-// Prove constructs a merkle proof for key. The result contains all encoded nodes
-// on the path to the value at key. The value itself is also included in the last
-// node and can be retrieved by verifying the proof.
-//
-// If the trie does not contain a value for key, the returned proof contains all
-// nodes of the longest existing prefix of the key (at least the root node), ending
-// with the node that proves the absence of the key.
-// func (t *SecureTrie) Prove(key []byte, fromLevel uint, proofDb store.Putter) error {
-// 	return t.trie.Prove(key, fromLevel, proofDb)
-// }
+// Prove constructs a merkle proof for key. The key is hashed before searching, so the proof should be verified by VerifySecureProof
+func (t *SecureTrie) Prove(key []byte, fromLevel uint, proofDb store.Putter) error {
+	return t.trie.Prove(crypto.Keccak256(key), fromLevel, proofDb)
+}
+
+// VerifySecureProof checks merkle proofs which are constructed by SecureTrie. The value is nil if the trie is empty
+func VerifySecureProof(rootHash common.Hash, key []byte, proofDb store.DatabaseReader) ([]byte, error) {
+	if rootHash == (common.Hash{}) || rootHash == emptyRoot {
+		return nil, nil
+	}
+	value, err, _ := VerifyProof(rootHash, crypto.Keccak256(key), proofDb)
+	return value, err
+}
 
 // VerifyProof checks merkle proofs. The given proof must contain the value for
 // key in a trie with the given root hash. VerifyProof returns an error if the